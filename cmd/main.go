@@ -48,6 +48,11 @@ import (
 	ramenv1alpha1 "github.com/ramendr/ramen/api/v1alpha1"
 	odfgsapiv1b1 "github.com/red-hat-storage/external-snapshotter/client/v8/apis/volumegroupsnapshot/v1beta1"
 	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 	admrv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -65,8 +70,10 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
@@ -115,11 +122,17 @@ func init() {
 func main() {
 	var metricsAddr, healthProbeAddr string
 	var webhookPort, consolePort int
+	var enableDebugEndpoints, enableTracing bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "The address the metrics endpoint binds to.")
 	flag.StringVar(&healthProbeAddr, "health-probe-bind-address", ":8081", "The address the health probe endpoint binds to.")
 	flag.IntVar(&webhookPort, "webhook-port", 7443, "The port the webhook sever binds to.")
 	flag.IntVar(&consolePort, "console-port", 9001, "The port where the console server will be serving it's payload")
+	flag.BoolVar(&enableDebugEndpoints, "enable-debug-endpoints", false,
+		"Serve the debug endpoints, like triggering a reconcile or summarizing the health of the managed resources, from the metrics server.")
+	flag.BoolVar(&enableTracing, "enable-tracing", false,
+		"Export OpenTelemetry traces of the operator configmap reconciles over OTLP/gRPC to the collector set by the "+
+			"standard OTEL_EXPORTER_OTLP_* environment variables, e.g. OTEL_EXPORTER_OTLP_ENDPOINT.")
 
 	opts := zap.Options{}
 	opts.BindFlags(flag.CommandLine)
@@ -162,6 +175,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	if supportedCSIImageVersions, err := controller.GetSupportedCSIImageVersions(apiCtx, apiClient, operatorNamespace); err != nil {
+		setupLog.Error(err, "unable to get the platform versions supported by the csi image sets")
+	} else {
+		setupLog.Info("csi image sets are available for platform versions", "versions", supportedCSIImageVersions)
+	}
+
 	err = utils.ValidateStausReporterImage()
 	if err != nil {
 		setupLog.Error(err, "unable to validate status reporter image")
@@ -238,6 +257,16 @@ func main() {
 		}},
 	)
 
+	setupLog.Info("registering operator ConfigMap defaulting webhook endpoint")
+	hookServer.Register(templates.ConfigMapDefaultingWebhookPath, &webhook.Admission{
+		Handler: &admwebhook.ConfigMapDefaulter{
+			Decoder:  admission.NewDecoder(mgr.GetScheme()),
+			Log:      mgr.GetLogger().WithName("webhook.configmap"),
+			Name:     alert.OperatorConfigMapName,
+			Defaults: controller.OperatorConfigMapDefaults(),
+		}},
+	)
+
 	if err = (&controller.StorageClientReconciler{
 		Client:               mgr.GetClient(),
 		Scheme:               mgr.GetScheme(),
@@ -275,6 +304,54 @@ func main() {
 		cancel()
 	}
 
+	var manualReconcileTrigger chan event.GenericEvent
+	if enableDebugEndpoints {
+		manualReconcileTrigger = make(chan event.GenericEvent, 1)
+		if err := mgr.AddMetricsServerExtraHandler(
+			"/debug/reconcile",
+			controller.NewManualReconcileHandler(manualReconcileTrigger, operatorNamespace),
+		); err != nil {
+			setupLog.Error(err, "unable to set up manual reconcile debug endpoint")
+			os.Exit(1)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(
+			"/debug/csi-image-versions",
+			controller.NewSupportedCSIImageVersionsHandler(mgr.GetAPIReader(), operatorNamespace),
+		); err != nil {
+			setupLog.Error(err, "unable to set up csi image versions debug endpoint")
+			os.Exit(1)
+		}
+		if err := mgr.AddMetricsServerExtraHandler(
+			"/debug/health",
+			controller.NewHealthSummaryHandler(mgr.GetAPIReader(), operatorNamespace),
+		); err != nil {
+			setupLog.Error(err, "unable to set up health summary debug endpoint")
+			os.Exit(1)
+		}
+	}
+
+	var tracer trace.Tracer
+	if enableTracing {
+		exporter, err := otlptracegrpc.New(mgrCtx)
+		if err != nil {
+			setupLog.Error(err, "unable to create OTLP trace exporter")
+			os.Exit(1)
+		}
+		tracerProvider := sdktrace.NewTracerProvider(
+			sdktrace.WithBatcher(exporter),
+			sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", "ocs-client-operator"))),
+		)
+		// flush the buffered spans once the manager stops
+		if err := mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+			<-ctx.Done()
+			return tracerProvider.Shutdown(context.Background())
+		})); err != nil {
+			setupLog.Error(err, "unable to add tracer provider shutdown to manager")
+			os.Exit(1)
+		}
+		tracer = tracerProvider.Tracer("ocs-client-operator")
+	}
+
 	if err = (&controller.OperatorConfigMapReconciler{
 		Client:                  mgr.GetClient(),
 		Scheme:                  mgr.GetScheme(),
@@ -283,6 +360,9 @@ func main() {
 		AvailableCrds:           availCrdsOrResources,
 		TlsProfile:              startupProfile,
 		UpdateAlertPollInterval: alertRunnable.SetPollInterval,
+		Recorder:                mgr.GetEventRecorder("ocs-client-operator"),
+		ManualReconcileTrigger:  manualReconcileTrigger,
+		Tracer:                  tracer,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OperatorConfigMapReconciler")
 		os.Exit(1)
@@ -329,7 +409,7 @@ func main() {
 
 	alertCollector := alert.NewCollector(alertRunnable)
 	resourceCollector := alert.NewResourceCollector(mgr.GetClient(), operatorNamespace)
-	metrics.Registry.MustRegister(alertCollector, resourceCollector)
+	metrics.Registry.MustRegister(alertCollector, resourceCollector, controller.ResourceDriftCorrections)
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(mgrCtx); err != nil {
@@ -379,18 +459,40 @@ func buildCacheAvailableCRDs(
 			LabelSelector: noobaaLabelSelector,
 		},
 	}
+	// PrometheusRules mirrored outside the operator namespace can't be owned and are tracked by label instead
+	prometheusRuleCacheByNamespace := map[string]cache.Config{
+		operatorNamespace: {},
+		cache.AllNamespaces: {
+			LabelSelector: labels.SelectorFromSet(labels.Set{controller.ManagedByLabelKey: controller.ManagedByLabelValue}),
+		},
+	}
 	cacheAvailableCrd := cache.Options{
 		ByObject: map[client.Object]cache.ByObject{
 			&admrv1.ValidatingWebhookConfiguration{}: {
 				// only cache our validation webhook
 				Field: subscriptionwebhookSelector,
 			},
+			&admrv1.MutatingWebhookConfiguration{}: {
+				// only cache our mutating webhook
+				Field: fields.SelectorFromSet(fields.Set{"metadata.name": templates.ConfigMapDefaultingWebhookName}),
+			},
 			&corev1.ConfigMap{}: {
 				Namespaces: configMapAndSecretCacheByNamespace,
 			},
 			&corev1.Secret{}: {
 				Namespaces: configMapAndSecretCacheByNamespace,
 			},
+			&monitoringv1.PrometheusRule{}: {
+				Namespaces: prometheusRuleCacheByNamespace,
+			},
+			&corev1.Namespace{}: {
+				// only cache the operator namespace, whose labels are verified before deploying csi
+				Field: fields.SelectorFromSet(fields.Set{"metadata.name": operatorNamespace}),
+			},
+			// the operator's own subscription may be placed outside the operator namespace by OLM
+			&opv1a1.Subscription{}: {
+				Namespaces: map[string]cache.Config{cache.AllNamespaces: {}},
+			},
 		},
 		DefaultNamespaces: defaultNamespaces,
 	}
@@ -0,0 +1,32 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ResourceDriftCorrections counts the updates the operator issues to bring an existing managed resource back
+// to its desired state. A steadily growing count for a kind usually means another controller is fighting
+// the operator over that resource.
+var ResourceDriftCorrections = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "ocs_client_operator_resource_drift_corrections_total",
+		Help: "Number of updates issued to correct drift of operator managed resources",
+	},
+	[]string{"kind"},
+)
@@ -19,15 +19,21 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"maps"
+	"net/http"
 	"net/url"
+	"path"
 	"reflect"
 	goruntime "runtime"
 	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	// The embed package is required for the prometheus rule files
@@ -43,31 +49,58 @@ import (
 	"github.com/go-logr/logr"
 	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	secv1 "github.com/openshift/api/security/v1"
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	"github.com/prometheus/common/model"
 	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	admrv1 "k8s.io/api/admissionregistration/v1"
 	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	apivalidation "k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/diff"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/apimachinery/pkg/util/version"
 	k8sYAML "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/csaupgrade"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 )
 
 var (
@@ -76,6 +109,53 @@ var (
 	//go:embed client-alert-rules.yaml
 	clientAlertPrometheusRules  string
 	subPackageIndexerRegistered bool
+
+	// noopTracer is used when tracing is disabled, its spans are never recorded.
+	noopTracer = noop.NewTracerProvider().Tracer("")
+
+	// reservedLabelKeys are operator owned labels which user supplied labels must not overwrite.
+	reservedLabelKeys = []string{ManagedByLabelKey, OwnedByLabelKey, console.AppNameLabelKey}
+
+	// extraManifestAllowedGVKs are the kinds which may be applied from the extra manifests ConfigMap, all of them
+	// confined to the operator namespace. Secrets are left out as they would be kept in plain text. RoleBindings may
+	// only bind Roles, which the api server refuses to create with privileges the operator doesn't hold in the
+	// namespace, so that the ClusterRoles of the operator can't be handed out.
+	extraManifestAllowedGVKs = []schema.GroupVersionKind{
+		corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+		rbacv1.SchemeGroupVersion.WithKind("Role"),
+		rbacv1.SchemeGroupVersion.WithKind("RoleBinding"),
+	}
+
+	// supportedRBDFsTypes are the filesystems the rbd node plugin can format volumes with.
+	supportedRBDFsTypes = []string{"ext4", "xfs"}
+
+	// csiContainerImageSetKeys maps the containers of the CSI pods deployed by ceph-csi-operator to the imageset
+	// keys of their images.
+	csiContainerImageSetKeys = map[string]string{
+		templates.RBDPluginContainerName:        imageSetPluginKey,
+		templates.CephFsPluginContainerName:     imageSetPluginKey,
+		templates.NfsPluginContainerName:        imageSetPluginKey,
+		templates.ProvisionerContainerName:      "provisioner",
+		templates.AttacherContainerName:         "attacher",
+		templates.ResizerContainerName:          "resizer",
+		templates.SnapshotterContainerName:      "snapshotter",
+		templates.SnapshotMetadataContainerName: "snapshot-metadata",
+		"driver-registrar":                      imageSetRegistrarKey,
+		"csi-addons":                            "addons",
+	}
+
+	// requiredPermissions are verified once per operator start, so that RBAC trimmed by an admin is reported
+	// precisely instead of failing a reconcile midway. Namespaced ones are checked in the operator namespace.
+	requiredPermissions = []struct {
+		authorizationv1.ResourceAttributes
+		namespaced bool
+	}{
+		{ResourceAttributes: authorizationv1.ResourceAttributes{Verb: "create", Group: "csi.ceph.io", Resource: "operatorconfigs"}, namespaced: true},
+		{ResourceAttributes: authorizationv1.ResourceAttributes{Verb: "create", Group: "csi.ceph.io", Resource: "drivers"}, namespaced: true},
+		{ResourceAttributes: authorizationv1.ResourceAttributes{Verb: "patch", Group: "security.openshift.io", Resource: "securitycontextconstraints"}},
+		{ResourceAttributes: authorizationv1.ResourceAttributes{Verb: "create", Group: "monitoring.coreos.com", Resource: "prometheusrules"}, namespaced: true},
+		{ResourceAttributes: authorizationv1.ResourceAttributes{Verb: "create", Group: "console.openshift.io", Resource: "consoleplugins"}},
+	}
 )
 
 const (
@@ -87,6 +167,8 @@ const (
 	disableInstallPlanAutoApprovalKey = "disableInstallPlanAutoApproval"
 	subscriptionLabelKey              = "managed-by"
 	subscriptionLabelValue            = "webhook.subscription.ocs.openshift.io"
+	subscriptionWebhookOperationsKey  = "SUBSCRIPTION_WEBHOOK_OPERATIONS"
+	clientOperatorPackageName         = "ocs-client-operator"
 	generateRbdOMapInfoKey            = "generateRbdOMapInfo"
 	enableRbdDriverKey                = "enableRbdDriver"
 	enableCephFsDriverKey             = "enableCephFsDriver"
@@ -95,15 +177,190 @@ const (
 	// AlertPollIntervalKey is the ConfigMap key for the client alert polling interval.
 	AlertPollIntervalKey = "alertPollInterval"
 
+	// retainOnUninstallKey, if true, omits the owner references to this configmap on the managed resources so
+	// that they, and the CSI workloads deployed from them, aren't garbage collected when the operator is
+	// uninstalled. The resources are tracked via the owned-by label instead and have to be removed manually.
+	retainOnUninstallKey = "RETAIN_ON_UNINSTALL"
+
+	// logResourceDiffsKey, if true, logs the changes made to resources updated by the reconciler.
+	logResourceDiffsKey = "LOG_RESOURCE_DIFFS"
+
+	// deployConfigMapDefaultingWebhookKey, if true, registers a webhook filling in defaults for the unset keys of this configmap.
+	deployConfigMapDefaultingWebhookKey = "DEPLOY_CONFIGMAP_DEFAULTING_WEBHOOK"
+
+	// ocsMetricsLabelsKey holds "key: value" lines set as labels on the PrometheusRules.
+	ocsMetricsLabelsKey = "OCS_METRICS_LABELS"
+	// prometheusRuleNamespacesKey is a comma separated list of additional namespaces to mirror the PrometheusRules into.
+	prometheusRuleNamespacesKey = "PROMETHEUS_RULE_NAMESPACES"
+	// pvcPrometheusRulesOverrideConfigMapKey names a ConfigMap whose "rules.yaml" replaces the embedded pvc rules.
+	pvcPrometheusRulesOverrideConfigMapKey = "PVC_PROMETHEUS_RULES_OVERRIDE_CONFIGMAP"
+	pvcPrometheusRulesOverrideKey          = "rules.yaml"
+	// pvcRulesProvisionerSelector is the provisioner regex of the embedded pvc rules, selecting the volumes of
+	// both the rbd and the cephfs drivers. It's narrowed to the deployed drivers.
+	pvcRulesProvisionerSelector = `(.*rbd.csi.ceph.com)|(.*cephfs.csi.ceph.com)`
+	// prometheusRuleLabelsKeyPrefix followed by a namespace holds the labels of the PrometheusRules mirrored
+	// into that namespace, in the OCS_METRICS_LABELS format. Namespaces without the key use OCS_METRICS_LABELS.
+	prometheusRuleLabelsKeyPrefix = "PROMETHEUS_RULE_LABELS_"
+	// extraManifestsConfigMapKey names a ConfigMap in the operator namespace whose values are multi document yaml
+	// manifests the operator applies next to its own resources, pruning them once removed from the ConfigMap.
+	extraManifestsConfigMapKey = "EXTRA_MANIFESTS_CONFIGMAP"
+	// extraManifestLabelKey marks the objects applied from the extra manifests ConfigMap.
+	extraManifestLabelKey = "ocs.openshift.io/extra-manifest"
+	// csiConfigHistoryLimitKey, if set, keeps that many timestamped snapshots of the resolved csi operator config
+	// in the csiConfigHistoryConfigMapName ConfigMap, appending one on every change and pruning the oldest.
+	csiConfigHistoryLimitKey       = "CSI_CONFIG_HISTORY_LIMIT"
+	csiConfigHistoryConfigMapName  = "ocs-client-operator-config-history"
+	maxCSIConfigHistoryLimit       = 50
+	csiConfigHistoryEntryKeyLayout = "20060102T150405.000000000Z.json"
+	// statusConditionsKey holds the json serialized []metav1.Condition reported by the operator in the
+	// operatorStatusConfigMapName ConfigMap, which is written by the reconciler only, keeping the user supplied
+	// operator configmap free of operator written keys.
+	statusConditionsKey         = "status.conditions"
+	operatorStatusConfigMapName = "ocs-client-operator-status"
+	// prometheusRuleEvalIntervalKey, if set, overrides the evaluation interval of every group of the PrometheusRules.
+	prometheusRuleEvalIntervalKey = "PROMETHEUS_RULE_EVAL_INTERVAL"
+
+	// csiSocketPathKey overrides the unix socket path shared by the CSI controller plugin and its sidecars.
+	csiSocketPathKey = "CSI_SOCKET_PATH"
+	// csiRequiredSecretsKey is a comma separated list of secrets in the operator namespace which must exist
+	// before CSI is deployed, e.g. externally synced KMS credentials.
+	csiRequiredSecretsKey = "CSI_REQUIRED_SECRETS"
+	// csiPodAnnotationsKey holds "key: value" lines added to the pod annotations of the CSI controller and node plugins.
+	csiPodAnnotationsKey = "CSI_POD_ANNOTATIONS"
+	// csiNamespaceLabelsKey holds "key: value" lines added to the labels of the namespace the CSI pods run in, e.g.
+	// the pod security admission labels the privileged CSI pods require.
+	csiNamespaceLabelsKey = "CSI_NAMESPACE_LABELS"
+	// disableMeshInjectionKey, if true, opts the namespace the CSI pods run in out of the istio and linkerd
+	// sidecar auto injection, which breaks the CSI pods.
+	disableMeshInjectionKey = "DISABLE_MESH_INJECTION"
+	// manageNamespacePodSecurityKey, if true, labels the namespace the CSI pods run in to admit privileged pods.
+	// Otherwise a pod security admission level blocking them is only reported.
+	manageNamespacePodSecurityKey = "MANAGE_NAMESPACE_PSA"
+	// csiProvisionerFeatureGatesKey holds comma separated "Name=bool" pairs passed as --feature-gates to the provisioner.
+	csiProvisionerFeatureGatesKey = "CSI_PROVISIONER_FEATURE_GATES"
+	// csiEnableVolumeAttributesClassKey, if true, enables the VolumeAttributesClass feature gate of the
+	// provisioner and resizer when the cluster serves the VolumeAttributesClass API.
+	csiEnableVolumeAttributesClassKey = "CSI_ENABLE_VOLUME_ATTRIBUTES_CLASS"
+	// csiExtraCreateMetadataKey, if set, overrides whether the provisioner passes the pvc/pv names to the driver.
+	csiExtraCreateMetadataKey = "CSI_EXTRA_CREATE_METADATA"
+	// csiVolumeNamePrefixKey, if set, is passed as --volume-name-prefix to the provisioner, e.g. to identify the
+	// cluster in the backend image and subvolume names.
+	csiVolumeNamePrefixKey = "CSI_VOLUME_NAME_PREFIX"
+	// csiVolumeNameUUIDLengthKey, if set, is passed as --volume-name-uuid-length to the provisioner.
+	csiVolumeNameUUIDLengthKey = "CSI_VOLUME_NAME_UUID_LENGTH"
+	// csiAttacherTimeoutKey, if set, is passed as --timeout to the attacher, e.g. for backends where attaching
+	// exceeds the attacher default.
+	csiAttacherTimeoutKey = "CSI_ATTACHER_TIMEOUT"
+	// csiProvisionerRetryIntervalMaxKey, if set, is passed as --retry-interval-max to the provisioner, capping the
+	// backoff between retries of failed provisioning.
+	csiProvisionerRetryIntervalMaxKey = "CSI_PROVISIONER_RETRY_INTERVAL_MAX"
+	// rbdDefaultFsTypeKey, if set, is passed as --default-fstype to the rbd provisioner.
+	rbdDefaultFsTypeKey = "RBD_DEFAULT_FSTYPE"
+	// csiServerSideApplyKey, if true, server side applies the csi operator config and drivers so that fields set
+	// on them by other managers are left in place.
+	csiServerSideApplyKey = "CSI_SERVER_SIDE_APPLY"
+	// csiProvisionersSuspendedKey, if true, scales the controller plugins down to halt provisioning, leaving the
+	// node plugins serving the existing mounts.
+	csiProvisionersSuspendedKey = "CSI_PROVISIONERS_SUSPENDED"
+	// csiNodePluginUpdateStrategyKey sets the update strategy type of the node plugin daemonsets, RollingUpdate
+	// or OnDelete, leaving the ceph-csi-operator default in place if unset.
+	csiNodePluginUpdateStrategyKey = "CSI_NODE_PLUGIN_UPDATE_STRATEGY"
+	// csiSupportedArchitecturesKey holds the comma separated node architectures, e.g. "amd64,arm64", the CSI
+	// controller and node plugins are restricted to on clusters with mixed architecture nodes.
+	csiSupportedArchitecturesKey = "CSI_SUPPORTED_ARCHITECTURES"
+	// createCSIPDBKey, if true, keeps a PodDisruptionBudget for the controller plugin of every enabled driver.
+	createCSIPDBKey = "CREATE_CSI_PDB"
+	// csiRolloutWindowKey holds a daily "HH:MM-HH:MM" UTC window outside of which spec changes to the csi
+	// operator config and drivers are deferred.
+	csiRolloutWindowKey = "CSI_ROLLOUT_WINDOW"
+
+	// namespace label and annotation disabling the sidecar auto injection of istio and linkerd respectively
+	istioInjectionLabelKey     = "istio-injection"
+	linkerdInjectAnnotationKey = "linkerd.io/inject"
+	meshInjectionDisabledValue = "disabled"
+
+	// pod security admission label enforcing the level of the namespace, the privileged level admits the CSI pods
+	podSecurityEnforceLabelKey = "pod-security.kubernetes.io/enforce"
+	podSecurityPrivilegedLevel = "privileged"
+
+	// kubeletDirPathKey overrides the kubelet root directory on the nodes, from which ceph-csi-operator derives
+	// the plugin registration path passed to the node driver registrar. The registrar image itself is overridden
+	// under csiRegistrarImageKey.
+	kubeletDirPathKey = "KUBELET_DIR_PATH"
+
+	// cephFsMounterKey selects the client the cephfs node plugin mounts volumes with, "kernel" or "autodetect".
+	// The latter falls back to ceph-fuse on nodes whose kernel lacks cephfs support.
+	cephFsMounterKey = "CEPHFS_MOUNTER"
+
+	// mountClusterTrustBundleKey, if true, mounts the cluster wide trust bundle at the system ca path of the CSI pods.
+	mountClusterTrustBundleKey = "MOUNT_CLUSTER_TRUST_BUNDLE"
+
+	// operatorDeploymentLabelKey and operatorDeploymentLabelValue identify the operator's own deployment.
+	operatorDeploymentLabelKey   = "control-plane"
+	operatorDeploymentLabelValue = "controller-manager"
+
+	// fieldManagerName is the field manager of the server side applied resources. It matches the manager the api
+	// server derives from the user agent of the operator for its updates, which are migrated to the apply manager.
+	fieldManagerName = "ocs-client-operator"
+	// appliedHashAnnotationKey holds the hash of the state last server side applied to a csi resource.
+	appliedHashAnnotationKey = "ocs.openshift.io/applied-hash"
+
+	// pausedLabelKey set to "true" on a managed resource stops the reconciler from updating it.
+	pausedLabelKey = "reconcile.ocs.openshift.io/paused"
+
+	// ManagedByLabelKey identifies resources managed by this operator which can't carry an owner reference.
+	ManagedByLabelKey   = "app.kubernetes.io/managed-by"
+	ManagedByLabelValue = "ocs-client-operator"
+	// OwnedByLabelKey, set to ManagedByLabelValue, is stamped on every managed resource, e.g. for
+	// "oc get all -l ocs.openshift.io/owned-by". The resources are tracked by it while they carry no owner reference
+	// in retain mode.
+	OwnedByLabelKey = "ocs.openshift.io/owned-by"
+
 	operatorConfigMapFinalizer = "ocs-client-operator.ocs.openshift.io/storageused"
 	subPackageIndexName        = "index:subscriptionPackage"
 	csiImagesConfigMapLabel    = "ocs.openshift.io/csi-images-version"
 	cniNetworksAnnotationKey   = "k8s.v1.cni.cncf.io/networks"
 
+	// csiRBDPluginImageKey and csiRegistrarImageKey override the plugin image of the rbd driver and the node driver
+	// registrar image of all drivers, e.g. when they are mirrored to different registries. They are applied to
+	// copies of the selected imageset, csiImageSetConfigMapName for all drivers and csiRBDImageSetConfigMapName
+	// for the rbd driver.
+	csiRBDPluginImageKey        = "CSI_RBD_PLUGIN_IMAGE"
+	csiRegistrarImageKey        = "CSI_REGISTRAR_IMAGE"
+	csiImageSetConfigMapName    = "ocs-client-operator-csi-images"
+	csiRBDImageSetConfigMapName = "ocs-client-operator-csi-rbd-images"
+	imageSetPluginKey           = "plugin"
+	imageSetRegistrarKey        = "registrar"
+
 	// disableS3EndpointProxyKey, if true, disables deploying the s3 endpoint reverse proxy for the local/internal client.
 	disableS3EndpointProxyKey    = "disableS3EndpointProxy"
 	s3EndpointsConfigMapLabelKey = "ocs.openshift.io/hub-s3-endpoints"
 
+	// consoleProxyForwardAuthKey, if true, forwards the console user's bearer token to the proxied s3 endpoints.
+	consoleProxyForwardAuthKey = "CONSOLE_PROXY_FORWARD_AUTH"
+	// consoleProxyInsecureSkipVerifyKey, if true, disables verifying the certificates of the proxied s3 endpoints,
+	// e.g. for internal endpoints with self-signed certificates.
+	consoleProxyInsecureSkipVerifyKey = "CONSOLE_PROXY_INSECURE_SKIP_VERIFY"
+	// consoleProxyDisableCacheKey, if true, forbids caching the responses of the proxied s3 endpoints.
+	consoleProxyDisableCacheKey = "CONSOLE_PROXY_DISABLE_CACHE"
+	// consoleListenPortKey and consoleServicePortKey override the port nginx listens on and the port of the
+	// console service registered with the ConsolePlugin, e.g. when a sidecar proxy fronts nginx. The container port
+	// and the liveness probe of the console deployment follow the listen port.
+	consoleListenPortKey  = "CONSOLE_LISTEN_PORT"
+	consoleServicePortKey = "CONSOLE_SERVICE_PORT"
+	// consolePluginReplicasKey, if set, scales the console deployment to the given number of replicas. The rest
+	// of the deployment is left as deployed by OLM.
+	consolePluginReplicasKey = "CONSOLE_PLUGIN_REPLICAS"
+
+	// nginxRootConfKey holds the root nginx config in the console nginx configmap, its hash is kept on the
+	// console pod template under nginxRootConfHashAnnotationKey.
+	nginxRootConfKey               = "nginx.conf"
+	nginxRootConfHashAnnotationKey = "ocs.openshift.io/nginx-conf-hash"
+
+	// appliedLabelsAnnotationKey records the user supplied label keys applied to an object, keys missing from
+	// the config on the next reconcile are removed from the object.
+	appliedLabelsAnnotationKey = "ocs.openshift.io/applied-labels"
+
 	// nginx proxy config key pattern per client: proxy-<clientuid>.conf (all locations for this client in one key).
 	nginxProxyConfigKeyFmt = "proxy-%s.conf"
 
@@ -120,6 +377,33 @@ const (
 
 	ibmZCpuArch         = "s390x"
 	ibmZCpuAdjustFactor = 0.5
+
+	// ceph-csi-operator names the plugin workloads of a driver as <driver name><suffix>
+	csiCtrlPluginSuffix = "-ctrlplugin"
+	csiNodePluginSuffix = "-nodeplugin"
+
+	// requeue interval used when the reconcile is interrupted by the manager shutting down
+	contextCancelledRequeueAfter = 5 * time.Second
+	// requeue interval used while the subscription webhook waits for openshift to inject its CA bundle
+	webhookCAInjectionRequeueAfter = 10 * time.Second
+	// quiet period after the last configmap, subscription or installplan event of a burst before the reconcile runs
+	enqueueConfigMapRequestDebounce = 2 * time.Second
+	// consecutive reconcile failures of a resource after which its circuit breaker opens
+	circuitBreakerThreshold = 5
+	// interval at which a resource whose circuit breaker is open is retried, it's skipped by the reconciles between
+	circuitBreakerRetryInterval = 15 * time.Minute
+	// condition reported in the status configmap while the circuit breaker of a resource is open
+	conditionTypeDegraded = "Degraded"
+	// condition reported in the status configmap once the requiredPermissions are verified
+	conditionTypePermissionsAvailable = "PermissionsAvailable"
+	// condition reported in the status configmap once the pod security level of the CSI namespace is verified
+	conditionTypeCSIPodSecurityAdmitted = "CSIPodSecurityAdmitted"
+	// condition reported in the status configmap once the endpoints of the webhook service are checked
+	conditionTypeWebhookEndpointsReady = "WebhookEndpointsReady"
+	// condition reported in the status configmap once the TLS verification of the console proxy is checked
+	conditionTypeConsoleProxyTLSVerified = "ConsoleProxyTLSVerified"
+	// condition reported in the status configmap once the secrets required by CSI are checked
+	conditionTypeCSIRequiredSecretsAvailable = "CSIRequiredSecretsAvailable"
 )
 
 // ConfigMapData value from the provider that contains the s3 endpoint info (key is the unique identifier, using which the endpoint is exposed).
@@ -136,17 +420,54 @@ type OperatorConfigMapReconciler struct {
 	AvailableCrds           map[string]bool
 	TlsProfile              *ocstlsv1.TLSProfile
 	UpdateAlertPollInterval func(time.Duration)
+	Recorder                events.EventRecorder
+	// ManualReconcileTrigger, if set, enqueues a reconcile of the operator configmap for every event received
+	ManualReconcileTrigger <-chan event.GenericEvent
+	// Tracer, if set, records a span for every reconcile with child spans for its major steps
+	Tracer trace.Tracer
 
 	log                 logr.Logger
 	ctx                 context.Context
 	operatorConfigMap   *corev1.ConfigMap
 	consoleDeployment   *appsv1.Deployment
 	subscriptionChannel string
+	// last observed readiness of the csi drivers, keyed by driver name
+	csiDriverReadiness map[string]bool
+	// resolved csi operator config spec of the last reconcile, in unstructured form
+	resolvedCSIOperatorConfig map[string]any
+	// time until the csi rollout window opens, zero while inside the window
+	csiRolloutWindowWait time.Duration
+	csiRolloutDeferred   bool
+	// consecutive reconcile failures, keyed by resource
+	resourceFailures map[string]int
+	// time at which the resources whose circuit breaker is open are retried, keyed by resource
+	resourceRetryTimes map[string]time.Time
+	// set when the circuit breaker of a resource is open during the current reconcile
+	circuitBreakerOpen bool
+	// conditions reported in the status configmap, loaded from its statusConditionsKey
+	conditions []metav1.Condition
+	// set once the requiredPermissions are verified
+	permissionsChecked bool
+	// set while the subscription webhook has no CA bundle injected, deferring its Fail failure policy
+	webhookCAPending bool
+	// drivers deployed by the last csi reconcile, nil until then
+	deployedCSIDrivers map[string]bool
+	// names of the ConfigMaps referenced from the operator configmap as of the last reconcile, read by the watch
+	// predicates without a round trip to the operator configmap
+	referencedConfigMaps *atomic.Pointer[[]string]
+	// names of the secrets required by CSI as of the last reconcile, read by the watch predicates
+	csiRequiredSecrets *atomic.Pointer[[]string]
+	// user supplied labels with a reserved key which were already reported, keyed by object and label key
+	reportedReservedLabels map[string]bool
+	// hash of the target images of the last reported csi image upgrade plan
+	reportedCSIImageUpgrade string
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	ctx := context.Background()
+	c.referencedConfigMaps = &atomic.Pointer[[]string]{}
+	c.csiRequiredSecrets = &atomic.Pointer[[]string]{}
 	if err := addSubscriptionPackageIndexer(ctx, mgr); err != nil {
 		return err
 	}
@@ -188,27 +509,39 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 					return true
 				}
 
+				if c.isReferencedConfigMap(obj.GetName()) {
+					return true
+				}
+
 				labels := obj.GetLabels()
 				return labels != nil && labels[s3EndpointsConfigMapLabelKey] == strconv.FormatBool(true)
 			},
 		),
 	)
-	// Reconcile the OperatorConfigMap object when the cluster's version object is updated
+	// Reconcile the OperatorConfigMap object when any of the watched objects is updated
+	operatorConfigMapRequest := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      operatorConfigMapName,
+			Namespace: c.OperatorNamespace,
+		},
+	}
 	enqueueConfigMapRequest := handler.EnqueueRequestsFromMapFunc(
 		func(_ context.Context, _ client.Object) []reconcile.Request {
-			return []reconcile.Request{{
-				NamespacedName: types.NamespacedName{
-					Name:      operatorConfigMapName,
-					Namespace: c.OperatorNamespace,
-				},
-			}}
+			return []reconcile.Request{operatorConfigMapRequest}
 		},
 	)
+	// configmap edits and OLM churn come in bursts, their events are coalesced into a single reconcile
+	debouncedEnqueueConfigMapRequest := newDebouncedEnqueueHandler(operatorConfigMapRequest, enqueueConfigMapRequestDebounce)
 
 	subscriptionPredicates := builder.WithPredicates(
 		predicate.NewPredicateFuncs(
-			func(client client.Object) bool {
-				return client.GetNamespace() == c.OperatorNamespace
+			func(obj client.Object) bool {
+				if obj.GetNamespace() == c.OperatorNamespace {
+					return true
+				}
+				// OLM may place the operator's own subscription outside the operator namespace
+				sub, ok := obj.(*opv1a1.Subscription)
+				return ok && sub.Spec != nil && sub.Spec.Package == clientOperatorPackageName
 			},
 		),
 		predicate.LabelChangedPredicate{},
@@ -222,6 +555,24 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		),
 	)
 
+	webhookEndpointSlicePredicates := builder.WithPredicates(
+		predicate.NewPredicateFuncs(
+			func(obj client.Object) bool {
+				return obj.GetNamespace() == c.OperatorNamespace &&
+					obj.GetLabels()[discoveryv1.LabelServiceName] == templates.WebhookServiceName
+			},
+		),
+	)
+
+	mutatingWebhookPredicates := builder.WithPredicates(
+		utils.NamePredicate(templates.ConfigMapDefaultingWebhookName),
+	)
+
+	namespacePredicates := builder.WithPredicates(
+		utils.NamePredicate(c.OperatorNamespace),
+		predicate.LabelChangedPredicate{},
+	)
+
 	servicePredicate := builder.WithPredicates(
 		predicate.NewPredicateFuncs(
 			func(obj client.Object) bool {
@@ -230,10 +581,12 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		),
 	)
 
-	s3EndpointCASecretPredicates := builder.WithPredicates(
+	// the missing secrets required by CSI are awaited through this watch instead of polling for them
+	secretPredicates := builder.WithPredicates(
 		predicate.NewPredicateFuncs(
 			func(obj client.Object) bool {
-				return obj.GetNamespace() == c.OperatorNamespace && obj.GetName() == s3EndpointCASecretName
+				return obj.GetNamespace() == c.OperatorNamespace &&
+					(obj.GetName() == s3EndpointCASecretName || c.isCSIRequiredSecret(obj.GetName()))
 			},
 		),
 	)
@@ -251,18 +604,54 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	generationChangePredicate := predicate.GenerationChangedPredicate{}
 
-	enqueueOwnerConfigMapRequest := handler.EnqueueRequestForOwner(
-		mgr.GetScheme(),
-		mgr.GetRESTMapper(),
-		&corev1.ConfigMap{},
-		handler.OnlyControllerOwner(),
+	// recreate or restore the scc promptly when it's deleted or edited out-of-band
+	sccPredicates := builder.WithPredicates(
+		predicate.NewPredicateFuncs(isOwnedByOperatorConfigMap),
+	)
+
+	// the pdbs of the csi controller plugins follow their deployments, the node plugins are observed by the
+	// reconciles triggered from the other watches
+	csiCtrlPluginPredicate := predicate.NewPredicateFuncs(
+		func(obj client.Object) bool {
+			return obj.GetNamespace() == c.OperatorNamespace &&
+				slices.Contains([]string{templates.RBDDriverName, templates.CephFsDriverName, templates.NfsDriverName},
+					strings.TrimSuffix(obj.GetName(), csiCtrlPluginSuffix))
+		},
+	)
+
+	// the console deployment is patched on top of the CSV, OLM rewriting its spec has to be caught
+	consoleDeploymentPredicate := predicate.And(
+		predicate.NewPredicateFuncs(func(obj client.Object) bool {
+			return obj.GetNamespace() == c.OperatorNamespace && obj.GetName() == console.DeploymentName
+		}),
+		predicate.GenerationChangedPredicate{},
+	)
+
+	// the csi controller plugins, the console and the operator deployment share a single deployment watch
+	deploymentPredicates := builder.WithPredicates(
+		predicate.Or(csiCtrlPluginPredicate, consoleDeploymentPredicate, newOperatorImageChangedPredicate(c.OperatorNamespace)),
+	)
+
+	// managed resources carry the managed-by label as they aren't owned by the configmap in retain mode
+	enqueueOwnerConfigMapRequest := handler.EnqueueRequestsFromMapFunc(
+		func(_ context.Context, obj client.Object) []reconcile.Request {
+			if obj.GetNamespace() != c.OperatorNamespace || !isOwnedByOperatorConfigMap(obj) {
+				return nil
+			}
+			return []reconcile.Request{{
+				NamespacedName: types.NamespacedName{
+					Name:      operatorConfigMapName,
+					Namespace: c.OperatorNamespace,
+				},
+			}}
+		},
 	)
 
 	bldr := ctrl.NewControllerManagedBy(mgr).
 		Named("OperatorConfigMapReconciler").
 		Watches(
 			&corev1.ConfigMap{},
-			enqueueConfigMapRequest,
+			debouncedEnqueueConfigMapRequest,
 			configMapPredicates,
 		).
 		Watches(
@@ -280,11 +669,13 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			enqueueOwnerConfigMapRequest,
 			builder.WithPredicates(generationChangePredicate),
 		).
+		Watches(&secv1.SecurityContextConstraints{}, enqueueConfigMapRequest, sccPredicates).
+		Watches(&appsv1.Deployment{}, enqueueConfigMapRequest, deploymentPredicates).
 		Watches(&configv1.ClusterVersion{}, enqueueConfigMapRequest, clusterVersionPredicates).
-		Watches(&opv1a1.Subscription{}, enqueueConfigMapRequest, subscriptionPredicates).
+		Watches(&opv1a1.Subscription{}, debouncedEnqueueConfigMapRequest, subscriptionPredicates).
 		Watches(
 			&opv1a1.InstallPlan{},
-			enqueueConfigMapRequest,
+			debouncedEnqueueConfigMapRequest,
 			builder.WithPredicates(
 				utils.EventTypePredicate(
 					true,
@@ -295,6 +686,9 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 			),
 		).
 		Watches(&admrv1.ValidatingWebhookConfiguration{}, enqueueConfigMapRequest, webhookPredicates).
+		Watches(&discoveryv1.EndpointSlice{}, enqueueConfigMapRequest, webhookEndpointSlicePredicates).
+		Watches(&admrv1.MutatingWebhookConfiguration{}, enqueueConfigMapRequest, mutatingWebhookPredicates).
+		Watches(&corev1.Namespace{}, enqueueConfigMapRequest, namespacePredicates).
 		Watches(
 			&v1alpha1.StorageClient{},
 			enqueueConfigMapRequest,
@@ -308,34 +702,97 @@ func (c *OperatorConfigMapReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Watches(
 			&corev1.Secret{},
 			enqueueConfigMapRequest,
-			s3EndpointCASecretPredicates,
+			secretPredicates,
 		)
+	if c.ManualReconcileTrigger != nil {
+		bldr = bldr.WatchesRawSource(source.Channel(c.ManualReconcileTrigger, &handler.EnqueueRequestForObject{}))
+	}
 
 	return bldr.Complete(c)
 }
 
+// newDebouncedEnqueueHandler returns an event handler which adds req to the queue once no event was received for
+// delay. Every event pushes the enqueue back, so a burst of events results in a single reconcile after it settled.
+func newDebouncedEnqueueHandler(req reconcile.Request, delay time.Duration) handler.EventHandler {
+	var lock sync.Mutex
+	var lastEvent time.Time
+	armed := false
+
+	var fire func(q workqueue.TypedRateLimitingInterface[reconcile.Request])
+	fire = func(q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		lock.Lock()
+		defer lock.Unlock()
+		// events received since the timer was armed re-arm it for the rest of the window
+		if wait := delay - time.Since(lastEvent); wait > 0 {
+			time.AfterFunc(wait, func() { fire(q) })
+			return
+		}
+		armed = false
+		q.Add(req)
+	}
+	enqueue := func(q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+		lock.Lock()
+		defer lock.Unlock()
+		lastEvent = time.Now()
+		if !armed {
+			armed = true
+			time.AfterFunc(delay, func() { fire(q) })
+		}
+	}
+	return handler.Funcs{
+		CreateFunc: func(_ context.Context, _ event.CreateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q)
+		},
+		UpdateFunc: func(_ context.Context, _ event.UpdateEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q)
+		},
+		DeleteFunc: func(_ context.Context, _ event.DeleteEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q)
+		},
+		GenericFunc: func(_ context.Context, _ event.GenericEvent, q workqueue.TypedRateLimitingInterface[reconcile.Request]) {
+			enqueue(q)
+		},
+	}
+}
+
 //+kubebuilder:rbac:groups=config.openshift.io,resources=clusterversions,verbs=get;list;watch
-//+kubebuilder:rbac:groups="apps",resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups="apps",resources=deployments;daemonsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups="apps",resources=deployments,verbs=patch
+//+kubebuilder:rbac:groups=events.k8s.io,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 //+kubebuilder:rbac:groups="apps",resources=deployments/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=configmaps/finalizers,verbs=update
 //+kubebuilder:rbac:groups="",resources=pods,verbs=list;watch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,verbs=get;list;watch;create;patch;update;delete
-//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update
+//+kubebuilder:rbac:groups=monitoring.coreos.com,resources=prometheusrules,verbs=get;list;watch;create;update;delete
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=console.openshift.io,resources=consoleplugins,verbs=*
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=subscriptions,verbs=get;list;watch;update;delete
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=installplans,verbs=get;list;watch;patch
 //+kubebuilder:rbac:groups=operators.coreos.com,resources=clusterserviceversions,verbs=delete;list
 //+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;list;update;create;watch;delete
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;list;update;create;watch;delete
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=operatorconfigs,verbs=get;list;update;create;watch;delete
 //+kubebuilder:rbac:groups=csi.ceph.io,resources=drivers,verbs=get;list;update;create;watch;delete
+//+kubebuilder:rbac:groups=csi.ceph.io,resources=operatorconfigs;drivers,verbs=patch
 //+kubebuilder:rbac:groups=config.openshift.io,resources=infrastructures,verbs=get;list;watch
+//+kubebuilder:rbac:groups=storage.k8s.io,resources=csidrivers,verbs=get
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=patch
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;delete
+//+kubebuilder:rbac:groups=authorization.k8s.io,resources=selfsubjectaccessreviews,verbs=create
 
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.8.3/pkg/reconcile
 func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := c.tracer().Start(ctx, "Reconcile", trace.WithAttributes(
+		attribute.String("namespace", req.Namespace),
+		attribute.String("name", req.Name),
+	))
+	defer span.End()
+
 	c.ctx = ctx
 	c.log = log.FromContext(ctx, "OperatorConfigMap", req)
 	c.log.Info("Reconciling OperatorConfigMap")
@@ -352,6 +809,10 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return reconcile.Result{}, err
 	}
 
+	c.loadConditions()
+	c.recordReferencedConfigMaps()
+	c.recordCSIRequiredSecrets()
+
 	alertPollInterval := alert.DefaultPollInterval
 	if val := c.operatorConfigMap.Data[AlertPollIntervalKey]; val != "" {
 		if d, err := time.ParseDuration(val); err == nil {
@@ -368,7 +829,7 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 		return reconcile.Result{}, err
 	}
 
-	disableVersionChecks, err := strconv.ParseBool(cmp.Or(c.operatorConfigMap.Data[disableVersionChecksKey], "false"))
+	disableVersionChecks, err := utils.ParseBool(cmp.Or(c.operatorConfigMap.Data[disableVersionChecksKey], "false"))
 	if err != nil {
 		c.log.Error(err, "failed to parse configmap key data", "key", disableVersionChecksKey)
 	}
@@ -380,6 +841,17 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 
 	if c.operatorConfigMap.GetDeletionTimestamp().IsZero() {
 
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
+		}
+
+		if !c.permissionsChecked {
+			if err := c.reportRequiredPermissions(); err != nil {
+				c.log.Error(err, "unable to verify the operator permissions")
+				return ctrl.Result{}, err
+			}
+		}
+
 		//ensure finalizer
 		if controllerutil.AddFinalizer(c.operatorConfigMap, operatorConfigMapFinalizer) {
 			c.log.Info("finalizer missing on the operatorConfigMap resource, adding...")
@@ -388,6 +860,7 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			}
 		}
 
+		c.webhookCAPending = false
 		if disableVersionChecks {
 			// delete the webhook if it exists
 			whConfig := &admrv1.ValidatingWebhookConfiguration{}
@@ -399,18 +872,31 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 					return ctrl.Result{}, err
 				}
 			}
+			if err := c.unlabelClientOperatorSubscription(); err != nil {
+				c.log.Error(err, "unable to remove the webhook label from the client operator subscription")
+				return ctrl.Result{}, err
+			}
 		} else {
 			if err := c.reconcileWebhookService(); err != nil {
 				c.log.Error(err, "unable to reconcile webhook service")
 				return ctrl.Result{}, err
 			}
 
-			if err := c.reconcileSubscriptionValidatingWebhook(); err != nil {
+			if err := c.traceStep("webhook", c.reconcileSubscriptionValidatingWebhook); err != nil {
 				c.log.Error(err, "unable to register subscription validating webhook")
 				return ctrl.Result{}, err
 			}
 		}
 
+		if err := c.traceStep("webhook", c.reconcileConfigMapDefaultingWebhook); err != nil {
+			c.log.Error(err, "unable to reconcile configmap defaulting webhook")
+			return ctrl.Result{}, err
+		}
+
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
+		}
+
 		if err := c.reconcileCSIAddonsOperatorSubscription(); err != nil {
 			c.log.Error(err, "unable to reconcile CSI Addons subscription")
 			return ctrl.Result{}, err
@@ -431,6 +917,10 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			return ctrl.Result{}, err
 		}
 
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
+		}
+
 		if c.shouldAutoApproveInstallPlans() {
 			if err := c.reconcileInstallPlans(); err != nil {
 				c.log.Error(err, "unable to reconcile InstallPlans")
@@ -438,53 +928,111 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 			}
 		}
 
-		if err := c.ensureConsolePlugin(); err != nil {
-			c.log.Error(err, "unable to deploy client console")
-			return ctrl.Result{}, err
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
 		}
 
-		if err := c.reconcileDelegatedCSI(storageClients); err != nil {
-			return ctrl.Result{}, err
+		c.circuitBreakerOpen = false
+
+		// the console isn't required for storage to function, its failures are retried after the csi setup
+		consoleErr := c.reconcileWithCircuitBreaker("ConsolePlugin", func() error {
+			return c.traceStep("console", c.ensureConsolePluginOrDegrade)
+		})
+
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
 		}
 
-		prometheusRule := &monitoringv1.PrometheusRule{}
-		if err := k8sYAML.NewYAMLOrJSONDecoder(bytes.NewBufferString(string(pvcPrometheusRules)), 1000).Decode(prometheusRule); err != nil {
-			c.log.Error(err, "Unable to retrieve prometheus rules.", "prometheusRule", klog.KRef(prometheusRule.Namespace, prometheusRule.Name))
+		csiRequiredSecretsAvailable, err := c.reportCSIRequiredSecrets()
+		if err != nil {
+			c.log.Error(err, "unable to verify the secrets required by CSI")
 			return ctrl.Result{}, err
 		}
 
-		prometheusRule.SetNamespace(c.OperatorNamespace)
-
-		err = c.createOrUpdate(prometheusRule, func() error {
-			applyLabels(c.operatorConfigMap.Data["OCS_METRICS_LABELS"], &prometheusRule.ObjectMeta)
-			return c.own(prometheusRule)
-		})
+		c.csiRolloutDeferred = false
+		c.csiRolloutWindowWait, err = getCSIRolloutWindowWait(c.operatorConfigMap.Data[csiRolloutWindowKey], time.Now())
 		if err != nil {
-			c.log.Error(err, "failed to create/update prometheus rules")
+			c.log.Error(err, "invalid csi rollout window")
 			return ctrl.Result{}, err
 		}
 
-		c.log.Info("prometheus rules deployed", "prometheusRule", klog.KRef(prometheusRule.Namespace, prometheusRule.Name))
+		// the secret watch triggers a reconcile once the missing secrets are created
+		if csiRequiredSecretsAvailable {
+			if err := c.traceStep("csi", func() error {
+				return c.reconcileDelegatedCSI(storageClients)
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		if c.csiRolloutDeferred {
+			c.log.Info("deferring csi spec changes until the rollout window opens", "requeueAfter", c.csiRolloutWindowWait)
+		}
 
-		clientAlertRule := &monitoringv1.PrometheusRule{}
-		if err := k8sYAML.NewYAMLOrJSONDecoder(bytes.NewBufferString(string(clientAlertPrometheusRules)), 1000).Decode(clientAlertRule); err != nil {
-			c.log.Error(err, "Unable to retrieve client alert prometheus rules.", "prometheusRule", klog.KRef(clientAlertRule.Namespace, clientAlertRule.Name))
+		if err := c.reportCSIDriverReadiness(); err != nil {
+			c.log.Error(err, "unable to report csi driver readiness")
 			return ctrl.Result{}, err
 		}
 
-		clientAlertRule.SetNamespace(c.OperatorNamespace)
+		if c.isContextCancelled() {
+			return ctrl.Result{RequeueAfter: contextCancelledRequeueAfter}, nil
+		}
 
-		err = c.createOrUpdate(clientAlertRule, func() error {
-			applyLabels(c.operatorConfigMap.Data["OCS_METRICS_LABELS"], &clientAlertRule.ObjectMeta)
-			return c.own(clientAlertRule)
-		})
+		pvcRule, err := c.getPvcPrometheusRule()
+		if err != nil {
+			c.log.Error(err, "Unable to retrieve prometheus rules.")
+			return ctrl.Result{}, err
+		}
+		if err := c.reconcileWithCircuitBreaker("PrometheusRule/"+pvcRule.Name, func() error {
+			return c.traceStep("prometheus", func() error { return c.reconcilePrometheusRule(pvcRule) })
+		}); err != nil {
+			c.log.Error(err, "failed to create/update prometheus rules")
+			return ctrl.Result{}, err
+		}
+
+		clientAlertRule, err := decodePrometheusRule(clientAlertPrometheusRules)
 		if err != nil {
+			c.log.Error(err, "Unable to retrieve client alert prometheus rules.")
+			return ctrl.Result{}, err
+		}
+		if err := c.reconcileWithCircuitBreaker("PrometheusRule/"+clientAlertRule.Name, func() error {
+			return c.traceStep("prometheus", func() error { return c.reconcilePrometheusRule(clientAlertRule) })
+		}); err != nil {
 			c.log.Error(err, "failed to create/update client alert prometheus rules")
 			return ctrl.Result{}, err
 		}
 
-		c.log.Info("client alert prometheus rules deployed", "prometheusRule", klog.KRef(clientAlertRule.Namespace, clientAlertRule.Name))
+		if err := c.reconcileExtraManifests(); err != nil {
+			c.log.Error(err, "failed to reconcile extra manifests")
+			return ctrl.Result{}, err
+		}
+
+		if c.circuitBreakerOpen {
+			c.setCondition(conditionTypeDegraded, metav1.ConditionTrue, "CircuitBreakerOpen",
+				"one or more resources keep failing to reconcile, see the Degraded events")
+		} else {
+			c.setCondition(conditionTypeDegraded, metav1.ConditionFalse, "AsExpected", "")
+		}
+		if err := c.reportConditions(); err != nil {
+			c.log.Error(err, "failed to report conditions in the status configmap")
+			return ctrl.Result{}, err
+		}
 
+		if consoleErr != nil {
+			return ctrl.Result{}, consoleErr
+		}
+		var requeueAfter time.Duration
+		if c.circuitBreakerOpen {
+			requeueAfter = circuitBreakerRetryInterval
+		}
+		if c.webhookCAPending {
+			requeueAfter = getShortestRequeueAfter(requeueAfter, webhookCAInjectionRequeueAfter)
+		}
+		if c.csiRolloutDeferred {
+			requeueAfter = getShortestRequeueAfter(requeueAfter, c.csiRolloutWindowWait)
+		}
+		if requeueAfter > 0 {
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
+		}
 	} else {
 		// deletion phase
 		if err := c.deletionPhase(); err != nil {
@@ -502,144 +1050,874 @@ func (c *OperatorConfigMapReconciler) Reconcile(ctx context.Context, req ctrl.Re
 	return ctrl.Result{}, nil
 }
 
-func (c *OperatorConfigMapReconciler) shouldAutoApproveInstallPlans() bool {
-	valueAsString, exist := c.operatorConfigMap.Data[disableInstallPlanAutoApprovalKey]
-	if !exist {
-		return true
+func decodePrometheusRule(rawRule string) (*monitoringv1.PrometheusRule, error) {
+	prometheusRule := &monitoringv1.PrometheusRule{}
+	if err := k8sYAML.NewYAMLOrJSONDecoder(bytes.NewBufferString(rawRule), 1000).Decode(prometheusRule); err != nil {
+		return nil, err
 	}
+	if prometheusRule.Name == "" {
+		return nil, fmt.Errorf("prometheus rule name is empty")
+	}
+	if len(prometheusRule.Spec.Groups) == 0 {
+		return nil, fmt.Errorf("prometheus rule %q has no rule groups", prometheusRule.Name)
+	}
+	return prometheusRule, nil
+}
 
-	disableInstallPlanAutoApproval, err := strconv.ParseBool(valueAsString)
+// getPvcPrometheusRule returns the embedded pvc PrometheusRule, or its replacement from the ConfigMap named under
+// PVC_PROMETHEUS_RULES_OVERRIDE_CONFIGMAP if that ConfigMap exists and carries non-empty rules.
+func (c *OperatorConfigMapReconciler) getPvcPrometheusRule() (*monitoringv1.PrometheusRule, error) {
+	defaultRule, err := decodePrometheusRule(pvcPrometheusRules)
 	if err != nil {
-		c.log.Error(err, "failed to parse configmap key data", "key", disableInstallPlanAutoApprovalKey)
-		return true
+		return nil, err
 	}
 
-	return !disableInstallPlanAutoApproval
+	c.filterPvcPrometheusRuleByDrivers(defaultRule)
+
+	overrideConfigMapName := strings.TrimSpace(c.operatorConfigMap.Data[pvcPrometheusRulesOverrideConfigMapKey])
+	if overrideConfigMapName == "" {
+		return defaultRule, nil
+	}
+
+	overrideConfigMap := &corev1.ConfigMap{}
+	overrideConfigMap.Name = overrideConfigMapName
+	overrideConfigMap.Namespace = c.OperatorNamespace
+	if err := c.get(overrideConfigMap); client.IgnoreNotFound(err) != nil {
+		return nil, fmt.Errorf("failed to get prometheus rules override ConfigMap %q: %v", overrideConfigMapName, err)
+	}
+	rawOverride := overrideConfigMap.Data[pvcPrometheusRulesOverrideKey]
+	if strings.TrimSpace(rawOverride) == "" {
+		c.log.Info("prometheus rules override not found, using embedded rules", "configMap", overrideConfigMapName)
+		return defaultRule, nil
+	}
+
+	overrideRule, err := decodePrometheusRule(rawOverride)
+	if err != nil {
+		return nil, fmt.Errorf("invalid prometheus rules override in ConfigMap %q: %v", overrideConfigMapName, err)
+	}
+	// the override replaces the embedded rules in place
+	overrideRule.Name = defaultRule.Name
+	c.log.Info("using prometheus rules override", "configMap", overrideConfigMapName)
+	return overrideRule, nil
 }
 
-func (c *OperatorConfigMapReconciler) reconcileInstallPlans() error {
-	approvePatch := client.RawPatch(types.MergePatchType, []byte(`{"spec":{"approved":true}}`))
-	installPlans := &opv1a1.InstallPlanList{}
-	if err := c.list(installPlans, client.InNamespace(c.OperatorNamespace)); err != nil {
+// reconcilePrometheusRule creates or updates the desired PrometheusRule in the operator namespace and mirrors
+// it into the namespaces listed under PROMETHEUS_RULE_NAMESPACES, removing mirrors that are no longer listed.
+func (c *OperatorConfigMapReconciler) reconcilePrometheusRule(desiredRule *monitoringv1.PrometheusRule) error {
+	evalInterval, err := c.getPrometheusRuleEvalInterval()
+	if err != nil {
 		return err
 	}
-	for idx := range installPlans.Items {
-		installPlan := &installPlans.Items[idx]
-		if installPlan.Spec.Approval == opv1a1.ApprovalManual && !installPlan.Spec.Approved {
-			if installPlan.Status.Phase != opv1a1.InstallPlanPhaseRequiresApproval {
-				return fmt.Errorf("installplan %s is not ready to be approved", installPlan.Name)
-			} else if err := c.Patch(c.ctx, installPlan, approvePatch); err != nil {
-				return err
-			}
+	desiredSpec := desiredRule.Spec.DeepCopy()
+	if evalInterval != nil {
+		for i := range desiredSpec.Groups {
+			desiredSpec.Groups[i].Interval = ptr.To(*evalInterval)
 		}
 	}
-	return nil
-}
 
-func (c *OperatorConfigMapReconciler) shouldEnableDriver(driverKey string) bool {
-	enableDriverVal, exists := c.operatorConfigMap.Data[driverKey]
-	if !exists {
-		return false
+	prometheusRule := &monitoringv1.PrometheusRule{}
+	prometheusRule.Name = desiredRule.Name
+	prometheusRule.Namespace = c.OperatorNamespace
+	if err := c.createOrUpdate(prometheusRule, func() error {
+		desiredSpec.DeepCopyInto(&prometheusRule.Spec)
+		c.applyLabels(c.getOperatorConfigValue(ocsMetricsLabelsKey, ""), &prometheusRule.ObjectMeta)
+		return c.own(prometheusRule)
+	}); err != nil {
+		return err
 	}
-	enableDriver, err := strconv.ParseBool(enableDriverVal)
-	if err != nil {
-		c.log.Error(err, "failed to parse configmap key data", "key", driverKey)
-		return false
+	c.log.Info("prometheus rules deployed", "prometheusRule", klog.KRef(prometheusRule.Namespace, prometheusRule.Name))
+
+	mirrorNamespaces := c.getPrometheusRuleMirrorNamespaces()
+	for _, namespace := range mirrorNamespaces {
+		// owner references can't span namespaces, mirrors are tracked by the managed-by label instead
+		mirrorRule := &monitoringv1.PrometheusRule{}
+		mirrorRule.Name = desiredRule.Name
+		mirrorRule.Namespace = namespace
+		// namespaces aren't cached beyond the operator namespace, a missing one surfaces as NotFound on create
+		if err := c.createOrUpdate(mirrorRule, func() error {
+			desiredSpec.DeepCopyInto(&mirrorRule.Spec)
+			c.applyLabels(c.getPrometheusRuleMirrorLabels(namespace), &mirrorRule.ObjectMeta)
+			addManagedLabels(mirrorRule)
+			return nil
+		}); kerrors.IsNotFound(err) {
+			c.log.Info("skipping missing prometheus rules mirror namespace", "namespace", namespace)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to mirror prometheus rules to namespace %q: %v", namespace, err)
+		}
+		c.log.Info("prometheus rules mirrored", "prometheusRule", klog.KRef(mirrorRule.Namespace, mirrorRule.Name))
 	}
-	return enableDriver
-}
 
-// getTopologyLabels returns a map of topology labels from the storage clients status and if the
-// storage clients status does not have any topology labels, it uses the configmap default values.
-func (c *OperatorConfigMapReconciler) getTopologyLabels(storageClients *v1alpha1.StorageClientList) map[string]struct{} {
-	topologyDomainLablesSet := map[string]struct{}{}
+	return c.deleteMirroredPrometheusRules(func(mirrorRule *monitoringv1.PrometheusRule) bool {
+		return mirrorRule.Name != desiredRule.Name || slices.Contains(mirrorNamespaces, mirrorRule.Namespace)
+	})
+}
 
-	// First, merge topology keys from StorageClient Status
-	// Multiple storage clients hubs can have different topology keys
-	for i := range storageClients.Items {
-		storageClient := &storageClients.Items[i]
-		if storageClient.Status.RbdDriverRequirements != nil {
-			// if the topology domain labels value is not empty, it should be a list of labels
-			// e.g. "region,zone"
-			for _, label := range storageClient.Status.RbdDriverRequirements.TopologyDomainLabels {
-				topologyDomainLablesSet[label] = struct{}{}
-			}
+// deleteMirroredPrometheusRules deletes the PrometheusRules mirrored out of the operator namespace, except the ones
+// for which keep returns true.
+func (c *OperatorConfigMapReconciler) deleteMirroredPrometheusRules(keep func(*monitoringv1.PrometheusRule) bool) error {
+	mirrorRules := &monitoringv1.PrometheusRuleList{}
+	if err := c.list(mirrorRules, client.MatchingLabels{ManagedByLabelKey: ManagedByLabelValue}); err != nil {
+		return fmt.Errorf("failed to list mirrored prometheus rules: %v", err)
+	}
+	for i := range mirrorRules.Items {
+		mirrorRule := &mirrorRules.Items[i]
+		if mirrorRule.Namespace == c.OperatorNamespace || keep(mirrorRule) {
+			continue
+		}
+		if err := c.delete(mirrorRule); err != nil {
+			return fmt.Errorf("failed to delete mirrored prometheus rules from namespace %q: %v", mirrorRule.Namespace, err)
 		}
+		c.log.Info("mirrored prometheus rules deleted", "prometheusRule", klog.KRef(mirrorRule.Namespace, mirrorRule.Name))
 	}
+	return nil
+}
 
-	// If no topology labels from StorageClient status, use ConfigMap defaults
-	if len(topologyDomainLablesSet) == 0 {
-		if configMapTopologyLabels := c.operatorConfigMap.Data[utils.TopologyFailureDomainLabelsKey]; configMapTopologyLabels != "" {
-			for label := range strings.SplitSeq(configMapTopologyLabels, ",") {
-				label = strings.TrimSpace(label)
-				if label != "" {
-					topologyDomainLablesSet[label] = struct{}{}
+// filterPvcPrometheusRuleByDrivers narrows the provisioner selector of the embedded pvc rules to the deployed rbd
+// and cephfs drivers, dropping the rules and groups left without any driver. The rules are kept as they are until
+// the deployed drivers are known.
+func (c *OperatorConfigMapReconciler) filterPvcPrometheusRuleByDrivers(rule *monitoringv1.PrometheusRule) {
+	if c.deployedCSIDrivers == nil {
+		return
+	}
+	var selectors []string
+	if c.deployedCSIDrivers[templates.RBDDriverName] {
+		selectors = append(selectors, `(.*rbd.csi.ceph.com)`)
+	}
+	if c.deployedCSIDrivers[templates.CephFsDriverName] {
+		selectors = append(selectors, `(.*cephfs.csi.ceph.com)`)
+	}
+	selector := strings.Join(selectors, "|")
+	if selector == pvcRulesProvisionerSelector {
+		return
+	}
+
+	var groups []monitoringv1.RuleGroup
+	for _, group := range rule.Spec.Groups {
+		var rules []monitoringv1.Rule
+		for _, groupRule := range group.Rules {
+			if expr := groupRule.Expr.String(); strings.Contains(expr, pvcRulesProvisionerSelector) {
+				if selector == "" {
+					continue
 				}
+				groupRule.Expr = intstr.FromString(strings.ReplaceAll(expr, pvcRulesProvisionerSelector, selector))
 			}
+			rules = append(rules, groupRule)
+		}
+		if len(rules) > 0 {
+			group.Rules = rules
+			groups = append(groups, group)
 		}
 	}
+	rule.Spec.Groups = groups
+}
 
-	return topologyDomainLablesSet
+// getPrometheusRuleMirrorLabels returns the labels of the PrometheusRules mirrored into namespace.
+func (c *OperatorConfigMapReconciler) getPrometheusRuleMirrorLabels(namespace string) string {
+	return c.getOperatorConfigValue(prometheusRuleLabelsKeyPrefix+namespace, c.getOperatorConfigValue(ocsMetricsLabelsKey, ""))
 }
 
-func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1alpha1.StorageClientList) error {
-	// scc
-	scc := &secv1.SecurityContextConstraints{}
-	scc.Name = templates.SCCName
-	if err := c.createOrUpdate(scc, func() error {
-		templates.SetSecurityContextConstraintsDesiredState(scc, c.OperatorNamespace)
-		return nil
-	}); err != nil {
-		return fmt.Errorf("failed to reconcile scc: %v", err)
+// recordReferencedConfigMaps records the names of the ConfigMaps referenced from the operator configmap, so that
+// the watch only lets their events through.
+func (c *OperatorConfigMapReconciler) recordReferencedConfigMaps() {
+	if c.referencedConfigMaps == nil {
+		return
 	}
-
-	// cluster version
-	clusterVersion := &configv1.ClusterVersion{}
-	clusterVersion.Name = clusterVersionName
-	if err := c.get(clusterVersion); err != nil {
-		return fmt.Errorf("failed to get cluster version: %v", err)
+	var names []string
+	for _, key := range []string{extraManifestsConfigMapKey, pvcPrometheusRulesOverrideConfigMapKey} {
+		if name := strings.TrimSpace(c.operatorConfigMap.Data[key]); name != "" {
+			names = append(names, name)
+		}
 	}
+	c.referencedConfigMaps.Store(&names)
+}
 
-	historyRecord := utils.Find(clusterVersion.Status.History, func(record *configv1.UpdateHistory) bool {
-		return record.State == configv1.CompletedUpdate
-	})
-	if historyRecord == nil {
-		return fmt.Errorf("unable to find the updated cluster version")
-	}
+// isReferencedConfigMap reports whether name was referenced from the operator configmap as of the last reconcile.
+func (c *OperatorConfigMapReconciler) isReferencedConfigMap(name string) bool {
+	names := c.referencedConfigMaps.Load()
+	return names != nil && slices.Contains(*names, name)
+}
 
-	isTnfCluster, err := c.checkIfTNFCluster()
+// reconcileExtraManifests server side applies the objects defined in the ConfigMap named under the
+// EXTRA_MANIFESTS_CONFIGMAP key into the operator namespace, owned like the operator's own resources, and deletes
+// the previously applied objects which are no longer defined. Only the extraManifestAllowedGVKs kinds are accepted.
+func (c *OperatorConfigMapReconciler) reconcileExtraManifests() error {
+	desired, err := c.getExtraManifests()
 	if err != nil {
 		return err
 	}
 
-	cniNetworkAnnotationValue := ""
-	topologyDomainLablesSet := c.getTopologyLabels(storageClients)
+	desiredKeys := map[string]bool{}
+	for _, obj := range desired {
+		// refuse to take over objects which weren't created from the extra manifests, e.g. the operator's own
+		live := &unstructured.Unstructured{}
+		live.SetGroupVersionKind(obj.GroupVersionKind())
+		live.SetName(obj.GetName())
+		live.SetNamespace(obj.GetNamespace())
+		if err := c.get(live); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get extra manifest %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		} else if err == nil && live.GetLabels()[extraManifestLabelKey] != "true" {
+			return fmt.Errorf("extra manifest %s %q conflicts with an existing object not created from the extra manifests",
+				obj.GetKind(), obj.GetName())
+		}
 
-	for i := range storageClients.Items {
-		storageClient := &storageClients.Items[i]
-		annotations := storageClient.GetAnnotations()
-		if annotationValue := annotations[cniNetworksAnnotationKey]; annotationValue != "" {
-			if cniNetworkAnnotationValue != "" {
-				return fmt.Errorf("only one client with CNI network annotation value is supported")
-			}
-			cniNetworkAnnotationValue = annotationValue
+		if err := c.own(obj); err != nil {
+			return fmt.Errorf("failed to own extra manifest %s %q: %v", obj.GetKind(), obj.GetName(), err)
+		}
+		utils.AddLabel(obj, extraManifestLabelKey, "true")
+		if err := c.Apply(c.ctx, client.ApplyConfigurationFromUnstructured(obj), client.FieldOwner(fieldManagerName)); err != nil {
+			return fmt.Errorf("failed to apply extra manifest %s %q: %v", obj.GetKind(), obj.GetName(), err)
 		}
+		desiredKeys[obj.GetKind()+"/"+obj.GetName()] = true
 	}
 
-	// csi operator config
-	cmName, err := c.getImageSetConfigMapName(historyRecord.Version)
-	if err != nil {
-		return fmt.Errorf("failed to get desired imageset configmap name: %v", err)
-	}
-	csiExtraArgs, err := buildContainerExtraArgs(c.TlsProfile)
-	if err != nil {
-		return err
+	for _, gvk := range extraManifestAllowedGVKs {
+		applied := &unstructured.UnstructuredList{}
+		applied.SetGroupVersionKind(gvk.GroupVersion().WithKind(gvk.Kind + "List"))
+		if err := c.list(applied, client.InNamespace(c.OperatorNamespace), client.MatchingLabels{extraManifestLabelKey: "true"}); err != nil {
+			return fmt.Errorf("failed to list applied extra manifests of kind %s: %v", gvk.Kind, err)
+		}
+		for i := range applied.Items {
+			obj := &applied.Items[i]
+			if desiredKeys[gvk.Kind+"/"+obj.GetName()] {
+				continue
+			}
+			if err := c.delete(obj); err != nil {
+				return fmt.Errorf("failed to prune extra manifest %s %q: %v", gvk.Kind, obj.GetName(), err)
+			}
+			c.log.Info("pruned extra manifest", "kind", gvk.Kind, "name", obj.GetName())
+		}
+	}
+	return nil
+}
+
+// getExtraManifests decodes the objects defined in the values of the extra manifests ConfigMap, in key order.
+func (c *OperatorConfigMapReconciler) getExtraManifests() ([]*unstructured.Unstructured, error) {
+	configMapName := strings.TrimSpace(c.getOperatorConfigValue(extraManifestsConfigMapKey, ""))
+	if configMapName == "" {
+		return nil, nil
+	}
+	manifestsConfigMap := &corev1.ConfigMap{}
+	manifestsConfigMap.Name = configMapName
+	manifestsConfigMap.Namespace = c.OperatorNamespace
+	if err := c.get(manifestsConfigMap); kerrors.IsNotFound(err) {
+		c.log.Info("extra manifests ConfigMap not found, pruning the applied extra manifests", "configMap", configMapName)
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get extra manifests ConfigMap %q: %v", configMapName, err)
+	}
+
+	var objs []*unstructured.Unstructured
+	seen := map[string]bool{}
+	for _, key := range slices.Sorted(maps.Keys(manifestsConfigMap.Data)) {
+		decoder := k8sYAML.NewYAMLOrJSONDecoder(strings.NewReader(manifestsConfigMap.Data[key]), 4096)
+		for {
+			obj := &unstructured.Unstructured{}
+			if err := decoder.Decode(&obj.Object); errors.Is(err, io.EOF) {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("invalid manifest under key %q of ConfigMap %q: %v", key, configMapName, err)
+			}
+			if len(obj.Object) == 0 {
+				continue
+			}
+			if !slices.Contains(extraManifestAllowedGVKs, obj.GroupVersionKind()) {
+				return nil, fmt.Errorf("kind %s of manifest %q under key %q of ConfigMap %q is not allowed, must be one of %v",
+					obj.GroupVersionKind(), obj.GetName(), key, configMapName, extraManifestAllowedGVKs)
+			}
+			if obj.GetName() == "" {
+				return nil, fmt.Errorf("manifest of kind %s under key %q of ConfigMap %q has no name", obj.GetKind(), key, configMapName)
+			}
+			if obj.GetKind() == "RoleBinding" {
+				if roleKind, _, _ := unstructured.NestedString(obj.Object, "roleRef", "kind"); roleKind != "Role" {
+					return nil, fmt.Errorf("manifest RoleBinding %q under key %q of ConfigMap %q must bind a Role, not %q",
+						obj.GetName(), key, configMapName, roleKind)
+				}
+			}
+			if ns := obj.GetNamespace(); ns != "" && ns != c.OperatorNamespace {
+				return nil, fmt.Errorf("manifest %s %q under key %q of ConfigMap %q must be in the operator namespace",
+					obj.GetKind(), obj.GetName(), key, configMapName)
+			}
+			objKey := obj.GetKind() + "/" + obj.GetName()
+			if seen[objKey] {
+				return nil, fmt.Errorf("manifest %s is defined more than once in ConfigMap %q", objKey, configMapName)
+			}
+			seen[objKey] = true
+			obj.SetNamespace(c.OperatorNamespace)
+			objs = append(objs, obj)
+		}
+	}
+	return objs, nil
+}
+
+// getPrometheusRuleEvalInterval returns the rule group evaluation interval configured under the
+// PROMETHEUS_RULE_EVAL_INTERVAL key, or nil to keep the interval of the rules.
+func (c *OperatorConfigMapReconciler) getPrometheusRuleEvalInterval() (*monitoringv1.Duration, error) {
+	value := strings.TrimSpace(c.getOperatorConfigValue(prometheusRuleEvalIntervalKey, ""))
+	if value == "" {
+		return nil, nil
+	}
+	if _, err := model.ParseDuration(value); err != nil {
+		return nil, fmt.Errorf("invalid value %q under %s key: %v", value, prometheusRuleEvalIntervalKey, err)
+	}
+	return ptr.To(monitoringv1.Duration(value)), nil
+}
+
+// getPrometheusRuleMirrorNamespaces returns the de-duplicated list of namespaces, other than the operator
+// namespace, that the PrometheusRules should be mirrored into.
+func (c *OperatorConfigMapReconciler) getPrometheusRuleMirrorNamespaces() []string {
+	var namespaces []string
+	for namespace := range strings.SplitSeq(c.operatorConfigMap.Data[prometheusRuleNamespacesKey], ",") {
+		namespace = strings.TrimSpace(namespace)
+		if namespace == "" || namespace == c.OperatorNamespace || slices.Contains(namespaces, namespace) {
+			continue
+		}
+		namespaces = append(namespaces, namespace)
+	}
+	return namespaces
+}
+
+// newOperatorImageChangedPredicate passes updates of the operator deployment in namespace which change any of its
+// container images, so that resources derived from the operator version are refreshed after an upgrade.
+func newOperatorImageChangedPredicate(namespace string) predicate.Predicate {
+	isOperatorDeployment := func(obj client.Object) bool {
+		return obj.GetNamespace() == namespace && obj.GetLabels()[operatorDeploymentLabelKey] == operatorDeploymentLabelValue
+	}
+	containerImages := func(obj client.Object) []string {
+		deployment, ok := obj.(*appsv1.Deployment)
+		if !ok {
+			return nil
+		}
+		var images []string
+		for _, container := range deployment.Spec.Template.Spec.Containers {
+			images = append(images, container.Image)
+		}
+		return images
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(event.CreateEvent) bool { return false },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isOperatorDeployment(e.ObjectNew) &&
+				!slices.Equal(containerImages(e.ObjectOld), containerImages(e.ObjectNew))
+		},
+	}
+}
+
+// getOperatorConfigValue returns the value of key in the operator configmap, or defaultValue if the configmap,
+// its data or the key is missing.
+func (c *OperatorConfigMapReconciler) getOperatorConfigValue(key, defaultValue string) string {
+	if c.operatorConfigMap == nil {
+		return defaultValue
+	}
+	if value, exist := c.operatorConfigMap.Data[key]; exist {
+		return value
+	}
+	return defaultValue
+}
+
+// isContextCancelled reports whether the reconcile context was cancelled, e.g. when the manager is shutting down,
+// in which case no further writes should be issued against the closing client.
+func (c *OperatorConfigMapReconciler) isContextCancelled() bool {
+	if err := c.ctx.Err(); err != nil {
+		c.log.Info("reconcile context cancelled, skipping remaining steps", "reason", err.Error())
+		return true
+	}
+	return false
+}
+
+func (c *OperatorConfigMapReconciler) shouldAutoApproveInstallPlans() bool {
+	valueAsString, exist := c.operatorConfigMap.Data[disableInstallPlanAutoApprovalKey]
+	if !exist {
+		return true
+	}
+
+	disableInstallPlanAutoApproval, err := utils.ParseBool(valueAsString)
+	if err != nil {
+		c.log.Error(err, "failed to parse configmap key data", "key", disableInstallPlanAutoApprovalKey)
+		return true
+	}
+
+	return !disableInstallPlanAutoApproval
+}
+
+func (c *OperatorConfigMapReconciler) reconcileInstallPlans() error {
+	approvePatch := client.RawPatch(types.MergePatchType, []byte(`{"spec":{"approved":true}}`))
+	installPlans := &opv1a1.InstallPlanList{}
+	if err := c.list(installPlans, client.InNamespace(c.OperatorNamespace)); err != nil {
+		return err
+	}
+	for idx := range installPlans.Items {
+		installPlan := &installPlans.Items[idx]
+		if installPlan.Spec.Approval == opv1a1.ApprovalManual && !installPlan.Spec.Approved {
+			if installPlan.Status.Phase != opv1a1.InstallPlanPhaseRequiresApproval {
+				return fmt.Errorf("installplan %s is not ready to be approved", installPlan.Name)
+			} else if err := c.Patch(c.ctx, installPlan, approvePatch); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *OperatorConfigMapReconciler) shouldEnableDriver(driverKey string) bool {
+	enableDriverVal, exists := c.operatorConfigMap.Data[driverKey]
+	if !exists {
+		return false
+	}
+	enableDriver, err := utils.ParseBool(enableDriverVal)
+	if err != nil {
+		c.log.Error(err, "failed to parse configmap key data", "key", driverKey)
+		return false
+	}
+	return enableDriver
+}
+
+// getTopologyLabels returns a map of topology labels from the storage clients status and if the
+// storage clients status does not have any topology labels, it uses the configmap default values.
+func (c *OperatorConfigMapReconciler) getTopologyLabels(storageClients *v1alpha1.StorageClientList) (map[string]struct{}, error) {
+	topologyDomainLablesSet := map[string]struct{}{}
+
+	// First, merge topology keys from StorageClient Status
+	// Multiple storage clients hubs can have different topology keys
+	for i := range storageClients.Items {
+		storageClient := &storageClients.Items[i]
+		if storageClient.Status.RbdDriverRequirements != nil {
+			// if the topology domain labels value is not empty, it should be a list of labels
+			// e.g. "region,zone"
+			for _, label := range storageClient.Status.RbdDriverRequirements.TopologyDomainLabels {
+				topologyDomainLablesSet[label] = struct{}{}
+			}
+		}
+	}
+
+	// If no topology labels from StorageClient status, use ConfigMap defaults
+	if len(topologyDomainLablesSet) == 0 {
+		configMapTopologyLabels := cmp.Or(
+			c.operatorConfigMap.Data[utils.CSITopologyDomainLabelsKey],
+			c.operatorConfigMap.Data[utils.TopologyFailureDomainLabelsKey],
+		)
+		for label := range strings.SplitSeq(configMapTopologyLabels, ",") {
+			label = strings.TrimSpace(label)
+			if label == "" {
+				continue
+			}
+			if errs := validation.IsQualifiedName(label); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid topology domain label %q in operator configmap: %s", label, strings.Join(errs, ", "))
+			}
+			topologyDomainLablesSet[label] = struct{}{}
+		}
+	}
+
+	return topologyDomainLablesSet, nil
+}
+
+// getCSIRequiredSecrets returns the distinct secrets listed under the CSI_REQUIRED_SECRETS key.
+func (c *OperatorConfigMapReconciler) getCSIRequiredSecrets() []string {
+	var secretNames []string
+	for secretName := range strings.SplitSeq(c.operatorConfigMap.Data[csiRequiredSecretsKey], ",") {
+		secretName = strings.TrimSpace(secretName)
+		if secretName != "" && !slices.Contains(secretNames, secretName) {
+			secretNames = append(secretNames, secretName)
+		}
+	}
+	return secretNames
+}
+
+// recordCSIRequiredSecrets records the names of the secrets required by CSI, so that the watch lets the events of
+// the missing ones through.
+func (c *OperatorConfigMapReconciler) recordCSIRequiredSecrets() {
+	if c.csiRequiredSecrets == nil {
+		return
+	}
+	names := c.getCSIRequiredSecrets()
+	c.csiRequiredSecrets.Store(&names)
+}
+
+// isCSIRequiredSecret reports whether name was required by CSI as of the last reconcile.
+func (c *OperatorConfigMapReconciler) isCSIRequiredSecret(name string) bool {
+	names := c.csiRequiredSecrets.Load()
+	return names != nil && slices.Contains(*names, name)
+}
+
+// getMissingCSIRequiredSecrets returns the secrets listed under the CSI_REQUIRED_SECRETS key which don't
+// exist in the operator namespace.
+func (c *OperatorConfigMapReconciler) getMissingCSIRequiredSecrets() ([]string, error) {
+	var missingSecrets []string
+	for _, secretName := range c.getCSIRequiredSecrets() {
+		secret := &corev1.Secret{}
+		secret.Name = secretName
+		secret.Namespace = c.OperatorNamespace
+		if err := c.get(secret); kerrors.IsNotFound(err) {
+			missingSecrets = append(missingSecrets, secretName)
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q: %v", secretName, err)
+		}
+	}
+	return missingSecrets, nil
+}
+
+// reportCSIRequiredSecrets reports in the CSIRequiredSecretsAvailable condition which of the secrets required by
+// CSI are missing, and returns whether all of them exist.
+func (c *OperatorConfigMapReconciler) reportCSIRequiredSecrets() (bool, error) {
+	missingSecrets, err := c.getMissingCSIRequiredSecrets()
+	if err != nil {
+		return false, err
+	}
+	if len(missingSecrets) == 0 {
+		c.setCondition(conditionTypeCSIRequiredSecretsAvailable, metav1.ConditionTrue, "SecretsFound", "")
+		return true, nil
+	}
+	c.log.Info("waiting for the secrets required by CSI to be created", "secrets", missingSecrets)
+	c.setCondition(conditionTypeCSIRequiredSecretsAvailable, metav1.ConditionFalse, "SecretsMissing",
+		fmt.Sprintf("CSI is not deployed until the secrets %s exist in namespace %q",
+			strings.Join(missingSecrets, ", "), c.OperatorNamespace))
+	return false, nil
+}
+
+// getCSIRolloutWindowWait returns how long after now the daily "HH:MM-HH:MM" UTC window opens, or zero if now
+// is inside the window or no window is configured. Windows ending before they start span midnight.
+func getCSIRolloutWindowWait(window string, now time.Time) (time.Duration, error) {
+	if strings.TrimSpace(window) == "" {
+		return 0, nil
+	}
+	startValue, endValue, found := strings.Cut(window, "-")
+	if !found {
+		return 0, fmt.Errorf("%s %q is not in the HH:MM-HH:MM format", csiRolloutWindowKey, window)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(startValue))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s start %q: %v", csiRolloutWindowKey, startValue, err)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(endValue))
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s end %q: %v", csiRolloutWindowKey, endValue, err)
+	}
+	if start.Equal(end) {
+		return 0, fmt.Errorf("%s %q is empty", csiRolloutWindowKey, window)
+	}
+
+	const day = 24 * time.Hour
+	now = now.UTC()
+	sinceMidnight := now.Sub(now.Truncate(day))
+	startOffset := start.Sub(start.Truncate(day))
+	endOffset := end.Sub(end.Truncate(day))
+
+	inWindow := sinceMidnight >= startOffset && sinceMidnight < endOffset
+	if startOffset > endOffset {
+		inWindow = sinceMidnight >= startOffset || sinceMidnight < endOffset
+	}
+	if inWindow {
+		return 0, nil
+	}
+	wait := startOffset - sinceMidnight
+	if wait < 0 {
+		wait += day
+	}
+	return wait, nil
+}
+
+// deferCSIRollout wraps f so that, outside the csi rollout window, spec changes to an existing csi resource are
+// reverted before the update and recorded as deferred. obj must be the csi operator config or a driver.
+func (c *OperatorConfigMapReconciler) deferCSIRollout(obj client.Object, f controllerutil.MutateFn) controllerutil.MutateFn {
+	return func() error {
+		existing := obj.DeepCopyObject()
+		if err := f(); err != nil {
+			return err
+		}
+		if c.csiRolloutWindowWait == 0 || obj.GetResourceVersion() == "" {
+			return nil
+		}
+		var deferred bool
+		switch desired := obj.(type) {
+		case *csiopv1.OperatorConfig:
+			deferred = restoreSpec(&desired.Spec, &existing.(*csiopv1.OperatorConfig).Spec)
+		case *csiopv1.Driver:
+			deferred = restoreSpec(&desired.Spec, &existing.(*csiopv1.Driver).Spec)
+		default:
+			return fmt.Errorf("unable to defer the rollout of %T", obj)
+		}
+		c.csiRolloutDeferred = c.csiRolloutDeferred || deferred
+		return nil
+	}
+}
+
+// restoreSpec sets desired back to existing, reporting whether they differed.
+func restoreSpec[T any](desired, existing *T) bool {
+	if equality.Semantic.DeepEqual(desired, existing) {
+		return false
+	}
+	*desired = *existing
+	return true
+}
+
+// reconcileCSIResource brings obj, the csi operator config or a driver, to the state set by f, deferring spec
+// changes to the rollout window. The state is server side applied when CSI_SERVER_SIDE_APPLY is set, f is then
+// called on obj carrying only its name and namespace. The fields previously updated by the operator are migrated to
+// its apply manager first, so that fields dropped from the desired state are removed rather than left owned by the
+// update manager, and the apply is skipped while the desired state is unchanged and still set on the resource.
+func (c *OperatorConfigMapReconciler) reconcileCSIResource(obj client.Object, f controllerutil.MutateFn) error {
+	serverSideApply, _ := utils.ParseBool(c.getOperatorConfigValue(csiServerSideApplyKey, "false"))
+	if !serverSideApply {
+		return c.createOrUpdate(obj, c.deferCSIRollout(obj, f))
+	}
+
+	kind := reflect.TypeOf(obj).Elem().Name()
+	live := obj.DeepCopyObject().(client.Object)
+	if err := c.get(live); kerrors.IsNotFound(err) {
+		live = nil
+	} else if err != nil {
+		return err
+	} else if live.GetLabels()[pausedLabelKey] == strconv.FormatBool(true) {
+		c.log.Info("skipping paused resource", "kind", kind, "name", obj.GetName(), "label", pausedLabelKey)
+		return nil
+	}
+
+	if err := f(); err != nil {
+		return err
+	}
+	desiredState, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return err
+	}
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme)
+	if err != nil {
+		return err
+	}
+	desired := &unstructured.Unstructured{Object: desiredState}
+	desired.SetGroupVersionKind(gvk)
+	unstructured.RemoveNestedField(desired.Object, "metadata", "creationTimestamp")
+	unstructured.RemoveNestedField(desired.Object, "status")
+	desiredJSON, err := json.Marshal(desired.Object)
+	if err != nil {
+		return err
+	}
+	desiredHash := utils.GetMD5Hash(string(desiredJSON))
+	annotations := desired.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[appliedHashAnnotationKey] = desiredHash
+	desired.SetAnnotations(annotations)
+	applyOpts := []client.ApplyOption{client.FieldOwner(fieldManagerName), client.ForceOwnership}
+
+	if live != nil {
+		upgradePatch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(fieldManagerName), fieldManagerName)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the managed fields of %s %q: %v", kind, live.GetName(), err)
+		}
+		if upgradePatch != nil {
+			if err := c.Patch(c.ctx, live, client.RawPatch(types.JSONPatchType, upgradePatch)); err != nil {
+				return fmt.Errorf("failed to migrate the managed fields of %s %q: %v", kind, live.GetName(), err)
+			}
+			c.log.Info("migrated managed fields to server side apply", "kind", kind, "name", live.GetName())
+		}
+
+		liveState, err := runtime.DefaultUnstructuredConverter.ToUnstructured(live)
+		if err != nil {
+			return err
+		}
+		if c.csiRolloutWindowWait != 0 && !isSubsetOf(desired.Object["spec"], liveState["spec"]) {
+			c.csiRolloutDeferred = true
+			return runtime.DefaultUnstructuredConverter.FromUnstructured(liveState, obj)
+		}
+		if live.GetAnnotations()[appliedHashAnnotationKey] == desiredHash &&
+			isSubsetOf(desired.Object["metadata"], liveState["metadata"]) &&
+			isSubsetOf(desired.Object["spec"], liveState["spec"]) {
+			return runtime.DefaultUnstructuredConverter.FromUnstructured(liveState, obj)
+		}
+	}
+
+	if err := c.Apply(c.ctx, client.ApplyConfigurationFromUnstructured(desired), applyOpts...); err != nil {
+		return err
+	}
+	if live == nil || desired.GetResourceVersion() != live.GetResourceVersion() {
+		c.log.Info("successfully applied", "kind", kind, "name", obj.GetName())
+	}
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(desired.Object, obj)
+}
+
+// isSubsetOf reports whether every field of the unstructured value subset is set to the same value in value, i.e.
+// whether applying subset leaves value unchanged. Lists are compared as a whole, empty and null fields of subset
+// match missing ones.
+func isSubsetOf(subset, value any) bool {
+	subsetMap, ok := subset.(map[string]any)
+	if !ok {
+		return equality.Semantic.DeepEqual(subset, value)
+	}
+	if len(subsetMap) == 0 {
+		return true
+	}
+	valueMap, ok := value.(map[string]any)
+	if !ok {
+		return false
+	}
+	for key, subsetField := range subsetMap {
+		if !isSubsetOf(subsetField, valueMap[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1alpha1.StorageClientList) error {
+	// cluster version
+	clusterVersion := &configv1.ClusterVersion{}
+	clusterVersion.Name = clusterVersionName
+	if err := c.get(clusterVersion); err != nil {
+		return fmt.Errorf("failed to get cluster version: %v", err)
+	}
+
+	historyRecord := utils.Find(clusterVersion.Status.History, func(record *configv1.UpdateHistory) bool {
+		return record.State == configv1.CompletedUpdate
+	})
+	if historyRecord == nil {
+		return fmt.Errorf("unable to find the updated cluster version")
+	}
+
+	isTnfCluster, err := c.checkIfTNFCluster()
+	if err != nil {
+		return err
+	}
+
+	cniNetworkAnnotationValue := ""
+	topologyDomainLablesSet, err := c.getTopologyLabels(storageClients)
+	if err != nil {
+		return err
+	}
+
+	for i := range storageClients.Items {
+		storageClient := &storageClients.Items[i]
+		annotations := storageClient.GetAnnotations()
+		if annotationValue := annotations[cniNetworksAnnotationKey]; annotationValue != "" {
+			if cniNetworkAnnotationValue != "" {
+				return fmt.Errorf("only one client with CNI network annotation value is supported")
+			}
+			cniNetworkAnnotationValue = annotationValue
+		}
+	}
+
+	// csi operator config
+	cmName, err := c.getImageSetConfigMapName(historyRecord.Version)
+	if err != nil {
+		return fmt.Errorf("failed to get desired imageset configmap name: %v", err)
+	}
+	cmName, rbdImageSetName, err := c.reconcileCSIImageSetOverrides(cmName)
+	if err != nil {
+		return err
+	}
+	if err := c.reportCSIImageUpgradePlan(cmName, rbdImageSetName); err != nil {
+		return err
+	}
+	csiExtraArgs, err := buildContainerExtraArgs(c.TlsProfile)
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs := addContainerExtraArgs(nil, csiExtraArgs)
+	nodePluginExtraArgs := addContainerExtraArgs(nil, csiExtraArgs)
+
+	csiSocketPathArgs, err := c.getCSISocketPathExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = setContainerExtraArgs(controllerPluginExtraArgs, csiSocketPathArgs)
+
+	provisionerFeatureGateArgs, err := c.getCSIProvisionerFeatureGateExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, provisionerFeatureGateArgs)
+
+	volumeAttributesClassArgs, err := c.getCSIVolumeAttributesClassExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, volumeAttributesClassArgs)
+
+	extraCreateMetadataArgs, err := c.getCSIExtraCreateMetadataExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, extraCreateMetadataArgs)
+
+	volumeNameArgs, err := c.getCSIVolumeNameExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, volumeNameArgs)
+
+	attacherTimeoutArgs, err := c.getCSIAttacherTimeoutExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, attacherTimeoutArgs)
+
+	retryIntervalMaxArgs, err := c.getCSIProvisionerRetryIntervalMaxExtraArgs()
+	if err != nil {
+		return err
+	}
+	controllerPluginExtraArgs = addContainerExtraArgs(controllerPluginExtraArgs, retryIntervalMaxArgs)
+
+	kubeletDirPath, err := c.getCSIKubeletDirPath()
+	if err != nil {
+		return err
+	}
+
+	rbdDefaultFsTypeArgs, err := c.getRBDDefaultFsTypeExtraArgs()
+	if err != nil {
+		return err
+	}
+
+	nodePluginUpdateStrategy, err := c.getCSINodePluginUpdateStrategy()
+	if err != nil {
+		return err
+	}
+
+	architectureAffinity, err := c.getCSIArchitectureAffinity()
+	if err != nil {
+		return err
+	}
+
+	cephFsClientType, err := c.getCephFsClientType()
+	if err != nil {
+		return err
+	}
+
+	provisionersSuspended := c.shouldSuspendCSIProvisioners()
+	if provisionersSuspended {
+		c.log.Info("csi provisioners are suspended, scaling the controller plugins down", "key", csiProvisionersSuspendedKey)
+	}
+
+	mountTrustBundle := c.shouldMountClusterTrustBundle()
+	if err := c.reconcileTrustedCABundleConfigMap(mountTrustBundle); err != nil {
+		return err
+	}
+
+	if err := c.reconcileCSINamespaceLabels(); err != nil {
+		return err
+	}
+	if err := c.reportCSINamespacePodSecurity(); err != nil {
+		return err
+	}
+
+	var requiredCtrlPluginAnnotations map[string]string
+	if cniNetworkAnnotationValue != "" {
+		requiredCtrlPluginAnnotations = map[string]string{cniNetworksAnnotationKey: cniNetworkAnnotationValue}
+	}
+	ctrlPluginAnnotations, err := c.buildCSIPodAnnotations(requiredCtrlPluginAnnotations)
+	if err != nil {
+		return err
+	}
+	nodePluginAnnotations, err := c.buildCSIPodAnnotations(nil)
+	if err != nil {
+		return err
 	}
 
 	csiOperatorConfig := &csiopv1.OperatorConfig{}
 	csiOperatorConfig.Name = templates.CSIOperatorConfigName
 	csiOperatorConfig.Namespace = c.OperatorNamespace
-	if err := c.createOrUpdate(csiOperatorConfig, func() error {
+	if err := c.reconcileCSIResource(csiOperatorConfig, func() error {
 		if err := c.own(csiOperatorConfig); err != nil {
 			return fmt.Errorf("failed to own csi operator config: %v", err)
 		}
@@ -658,26 +1936,49 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 		if c.AvailableCrds[VolumeGroupSnapshotClassCrdName] {
 			driverSpecDefaults.SnapshotPolicy = csiopv1.VolumeGroupSnapshotPolicy
 		}
-		if cniNetworkAnnotationValue != "" {
-			if driverSpecDefaults.ControllerPlugin.Annotations == nil {
-				driverSpecDefaults.ControllerPlugin.Annotations = map[string]string{}
-			}
-			driverSpecDefaults.ControllerPlugin.Annotations[cniNetworksAnnotationKey] = cniNetworkAnnotationValue
+		if len(ctrlPluginAnnotations) > 0 {
+			driverSpecDefaults.ControllerPlugin.Annotations = ctrlPluginAnnotations
+		}
+		if len(nodePluginAnnotations) > 0 {
+			driverSpecDefaults.NodePlugin.Annotations = nodePluginAnnotations
+		}
+		if kubeletDirPath != "" {
+			driverSpecDefaults.NodePlugin.KubeletDirPath = kubeletDirPath
 		}
+		if provisionersSuspended {
+			driverSpecDefaults.ControllerPlugin.Replicas = ptr.To(int32(0))
+		}
+		if architectureAffinity != nil {
+			driverSpecDefaults.ControllerPlugin.Affinity = architectureAffinity.DeepCopy()
+			driverSpecDefaults.NodePlugin.Affinity = architectureAffinity.DeepCopy()
+		}
+		if nodePluginUpdateStrategy != nil {
+			driverSpecDefaults.NodePlugin.UpdateStrategy = nodePluginUpdateStrategy
+		}
+		templates.SetTrustedCABundleVolume(&driverSpecDefaults.ControllerPlugin.PodCommonSpec, mountTrustBundle)
+		templates.SetTrustedCABundleVolume(&driverSpecDefaults.NodePlugin.PodCommonSpec, mountTrustBundle)
 		if len(topologyDomainLablesSet) > 0 {
 			driverSpecDefaults.NodePlugin.Topology = &csiopv1.TopologySpec{
-				DomainLabels: slices.Collect(maps.Keys(topologyDomainLablesSet)),
+				DomainLabels: slices.Sorted(maps.Keys(topologyDomainLablesSet)),
 			}
 		}
 		driverSpecDefaults.GenerateOMapInfo = ptr.To(c.shouldGenerateRBDOmapInfo())
-		if len(csiExtraArgs) > 0 {
-			driverSpecDefaults.ControllerPlugin.ContainerExtraArgs = csiExtraArgs
-			driverSpecDefaults.NodePlugin.ContainerExtraArgs = csiExtraArgs
+		if len(controllerPluginExtraArgs) > 0 {
+			driverSpecDefaults.ControllerPlugin.ContainerExtraArgs = controllerPluginExtraArgs
+		}
+		if len(nodePluginExtraArgs) > 0 {
+			driverSpecDefaults.NodePlugin.ContainerExtraArgs = nodePluginExtraArgs
 		}
 		return nil
 	}); err != nil {
 		return fmt.Errorf("failed to reconcile csi operator config: %v", err)
 	}
+	if err := c.reportCSIOperatorConfigChange(&csiOperatorConfig.Spec); err != nil {
+		return err
+	}
+	if err := c.recordCSIOperatorConfigHistory(&csiOperatorConfig.Spec); err != nil {
+		return err
+	}
 
 	enableRbdDriver := c.shouldEnableDriver(enableRbdDriverKey)
 	enableCephFsDriver := c.shouldEnableDriver(enableCephFsDriverKey)
@@ -707,12 +2008,55 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 		}
 	}
 
+	// scc
+	var enabledDrivers []string
+	if enableRbdDriver {
+		enabledDrivers = append(enabledDrivers, templates.RBDDriverName)
+	}
+	if enableCephFsDriver {
+		enabledDrivers = append(enabledDrivers, templates.CephFsDriverName)
+	}
+	if enableNfsDriver {
+		enabledDrivers = append(enabledDrivers, templates.NfsDriverName)
+	}
+	c.deployedCSIDrivers = map[string]bool{}
+	for _, driverName := range enabledDrivers {
+		c.deployedCSIDrivers[driverName] = true
+	}
+	// a disabled nfs driver is kept while volumes or snapshots still use it, its plugins need the scc meanwhile
+	retainNfsDriver := false
+	if !enableNfsDriver {
+		if hasPvs, err := c.hasPersistentVolumesWithNfsDriver(); err != nil {
+			return fmt.Errorf("failed to check if NFS driver has PVs: %v", err)
+		} else if hasPvs {
+			c.log.Info("NFS driver has PVs, skipping deletion")
+			retainNfsDriver = true
+		} else if hasVscs, err := c.hasVolumeSnapshotContentsWithNfsDriver(); err != nil {
+			return fmt.Errorf("failed to check if NFS driver has volumesnapshotcontents: %v", err)
+		} else if hasVscs {
+			c.log.Info("NFS driver has volumesnapshotcontents, skipping deletion")
+			retainNfsDriver = true
+		}
+	}
+	sccDrivers := enabledDrivers
+	if retainNfsDriver {
+		sccDrivers = append(slices.Clone(enabledDrivers), templates.NfsDriverName)
+	}
+	if err := c.traceStep("scc", func() error {
+		return c.reconcileSecurityContextConstraints(sccDrivers)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile scc: %v", err)
+	}
+	if err := c.reconcileCSIProvisionerPDBs(enabledDrivers); err != nil {
+		return err
+	}
+
 	// ceph rbd driver config
 	if enableRbdDriver {
 		rbdDriver := &csiopv1.Driver{}
 		rbdDriver.Name = templates.RBDDriverName
 		rbdDriver.Namespace = c.OperatorNamespace
-		if err := c.createOrUpdate(rbdDriver, func() error {
+		if err := c.reconcileCSIResource(rbdDriver, func() error {
 			if err := c.own(rbdDriver); err != nil {
 				return fmt.Errorf("failed to own csi rbd driver: %v", err)
 			}
@@ -720,7 +2064,19 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 				rbdDriver.Spec.ControllerPlugin = &csiopv1.ControllerPluginSpec{}
 			}
 			rbdDriver.Spec.ControllerPlugin.HostNetwork = ptr.To(useHostNetForRbdCtrlPlugin)
+			rbdDriver.Spec.ImageSet = nil
+			if rbdImageSetName != "" {
+				rbdDriver.Spec.ImageSet = &corev1.LocalObjectReference{Name: rbdImageSetName}
+			}
 			templates.InjectSnapshotMetadataTLSVolume(rbdDriver.Spec.ControllerPlugin)
+			// volumes set on the driver replace the ones from the operator config defaults
+			templates.SetTrustedCABundleVolume(&rbdDriver.Spec.ControllerPlugin.PodCommonSpec, mountTrustBundle)
+			// like the volumes, extra args set on the driver replace the operator config defaults
+			rbdDriver.Spec.ControllerPlugin.ContainerExtraArgs = nil
+			if len(rbdDefaultFsTypeArgs) > 0 {
+				rbdDriver.Spec.ControllerPlugin.ContainerExtraArgs = addContainerExtraArgs(
+					addContainerExtraArgs(nil, controllerPluginExtraArgs), rbdDefaultFsTypeArgs)
+			}
 			return nil
 		}); err != nil {
 			return fmt.Errorf("failed to reconcile rbd driver: %v", err)
@@ -738,7 +2094,7 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 		cephFsDriver := &csiopv1.Driver{}
 		cephFsDriver.Name = templates.CephFsDriverName
 		cephFsDriver.Namespace = c.OperatorNamespace
-		if err := c.createOrUpdate(cephFsDriver, func() error {
+		if err := c.reconcileCSIResource(cephFsDriver, func() error {
 			if err := c.own(cephFsDriver); err != nil {
 				return fmt.Errorf("failed to own csi cephfs driver: %v", err)
 			}
@@ -746,6 +2102,7 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 				cephFsDriver.Spec.ControllerPlugin = &csiopv1.ControllerPluginSpec{}
 			}
 			cephFsDriver.Spec.ControllerPlugin.HostNetwork = ptr.To(useHostNetForCephFsCtrlPlugin)
+			cephFsDriver.Spec.CephFsClientType = cephFsClientType
 			return nil
 		}); err != nil {
 			return fmt.Errorf("failed to reconcile cephfs driver: %v", err)
@@ -757,7 +2114,7 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 	nfsDriver.Name = templates.NfsDriverName
 	nfsDriver.Namespace = c.OperatorNamespace
 	if enableNfsDriver {
-		if err := c.createOrUpdate(nfsDriver, func() error {
+		if err := c.reconcileCSIResource(nfsDriver, func() error {
 			if err := c.own(nfsDriver); err != nil {
 				return fmt.Errorf("failed to own csi nfs driver: %v", err)
 			}
@@ -769,27 +2126,246 @@ func (c *OperatorConfigMapReconciler) reconcileDelegatedCSI(storageClients *v1al
 		}); err != nil {
 			return fmt.Errorf("failed to reconcile nfs driver: %v", err)
 		}
-	} else {
-		if hasPvs, err := c.hasPersistentVolumesWithNfsDriver(); err != nil {
-			return fmt.Errorf("failed to check if NFS driver has PVs: %v", err)
-		} else if hasPvs {
-			c.log.Info("NFS driver has PVs, skipping deletion")
-			return nil
+	} else if !retainNfsDriver {
+		if err := c.delete(nfsDriver); err != nil {
+			return fmt.Errorf("failed to delete csi nfs driver: %v", err)
 		}
-		if hasVscs, err := c.hasVolumeSnapshotContentsWithNfsDriver(); err != nil {
-			return fmt.Errorf("failed to check if NFS driver has volumesnapshotcontents: %v", err)
-		} else if hasVscs {
-			c.log.Info("NFS driver has volumesnapshotcontents, skipping deletion")
+	}
+
+	return nil
+}
+
+// reconcileSecurityContextConstraints creates or updates the CSI SCC for the plugins of driverNames, retrying on
+// conflicts as the SCC is cluster scoped and commonly edited concurrently by other actors. The SCC is kept even
+// when no driver is listed, as it's always granted to the nvmeof plugins.
+func (c *OperatorConfigMapReconciler) reconcileSecurityContextConstraints(driverNames []string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		scc := &secv1.SecurityContextConstraints{}
+		scc.Name = templates.SCCName
+		err := c.createOrUpdate(scc, func() error {
+			templates.SetSecurityContextConstraintsDesiredState(scc, c.OperatorNamespace, driverNames)
+			// cluster scoped scc can't be owned by the configmap, it's watched via the label instead
+			addManagedLabels(scc)
 			return nil
+		})
+		if kerrors.IsConflict(err) {
+			c.log.Info("conflict while updating scc, retrying", "name", scc.Name)
 		}
-		if err := c.delete(nfsDriver); err != nil {
-			return fmt.Errorf("failed to delete csi nfs driver: %v", err)
+		return err
+	})
+}
+
+// reconcileCSIProvisionerPDBs keeps a PodDisruptionBudget with maxUnavailable 1 for the controller plugin deployment,
+// which runs the provisioner, of each driver in driverNames when CREATE_CSI_PDB is set, removing it otherwise. The
+// budget doesn't block the drain of a single replica deployment, while still keeping a second replica up.
+func (c *OperatorConfigMapReconciler) reconcileCSIProvisionerPDBs(driverNames []string) error {
+	createPDBs, _ := utils.ParseBool(c.getOperatorConfigValue(createCSIPDBKey, "false"))
+	for _, driverName := range []string{templates.RBDDriverName, templates.CephFsDriverName, templates.NfsDriverName} {
+		pdb := &policyv1.PodDisruptionBudget{}
+		pdb.Name = driverName + csiCtrlPluginSuffix
+		pdb.Namespace = c.OperatorNamespace
+		if !createPDBs || !slices.Contains(driverNames, driverName) {
+			if err := c.get(pdb); kerrors.IsNotFound(err) {
+				continue
+			} else if err != nil {
+				return fmt.Errorf("failed to get pdb %q: %v", pdb.Name, err)
+			}
+			if err := c.delete(pdb); err != nil {
+				return fmt.Errorf("failed to delete pdb %q: %v", pdb.Name, err)
+			}
+			c.log.Info("pdb deleted", "pdb", klog.KObj(pdb))
+			continue
+		}
+
+		ctrlPlugin := &appsv1.Deployment{}
+		ctrlPlugin.Name = driverName + csiCtrlPluginSuffix
+		ctrlPlugin.Namespace = c.OperatorNamespace
+		if err := c.get(ctrlPlugin); kerrors.IsNotFound(err) {
+			// the deployment is watched, the pdb is created once ceph-csi-operator deploys the controller plugin
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get csi controller plugin %q: %v", ctrlPlugin.Name, err)
+		}
+		if err := c.createOrUpdate(pdb, func() error {
+			pdb.Spec.MinAvailable = nil
+			pdb.Spec.MaxUnavailable = ptr.To(intstr.FromInt32(1))
+			pdb.Spec.Selector = ctrlPlugin.Spec.Selector.DeepCopy()
+			return c.own(pdb)
+		}); err != nil {
+			return fmt.Errorf("failed to reconcile pdb %q: %v", pdb.Name, err)
+		}
+	}
+	return nil
+}
+
+// reportCSIOperatorConfigChange emits an event on the operator configmap listing the fields of the resolved csi
+// operator config that changed since the last reconcile. The last config is kept in memory, so no event is emitted
+// for the first reconcile after an operator restart.
+func (c *OperatorConfigMapReconciler) reportCSIOperatorConfigChange(spec *csiopv1.OperatorConfigSpec) error {
+	resolved, err := runtime.DefaultUnstructuredConverter.ToUnstructured(spec)
+	if err != nil {
+		return fmt.Errorf("failed to convert csi operator config: %v", err)
+	}
+	previous := c.resolvedCSIOperatorConfig
+	c.resolvedCSIOperatorConfig = resolved
+	if previous == nil {
+		return nil
+	}
+
+	changedFields := getChangedFields(previous, resolved, "")
+	if len(changedFields) == 0 {
+		return nil
+	}
+	c.log.Info("resolved csi operator config changed", "fields", changedFields)
+	if c.Recorder != nil {
+		c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeNormal, "CSIConfigChanged", "Update",
+			"resolved csi operator config changed: %s", strings.Join(changedFields, ", "))
+	}
+	return nil
+}
+
+// recordCSIOperatorConfigHistory appends the resolved csi operator config to the history ConfigMap whenever it
+// differs from the latest entry, keeping the newest CSI_CONFIG_HISTORY_LIMIT entries keyed by their UTC timestamp.
+// The history is left as is while the limit is unset.
+func (c *OperatorConfigMapReconciler) recordCSIOperatorConfigHistory(spec *csiopv1.OperatorConfigSpec) error {
+	limit, err := c.getCSIConfigHistoryLimit()
+	if err != nil || limit == 0 {
+		return err
+	}
+	resolved, err := json.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal csi operator config: %v", err)
+	}
+
+	history := &corev1.ConfigMap{}
+	history.Name = csiConfigHistoryConfigMapName
+	history.Namespace = c.OperatorNamespace
+	if err := c.createOrUpdate(history, func() error {
+		if err := c.own(history); err != nil {
+			return err
+		}
+		keys := slices.Sorted(maps.Keys(history.Data))
+		if len(keys) == 0 || history.Data[keys[len(keys)-1]] != string(resolved) {
+			if history.Data == nil {
+				history.Data = map[string]string{}
+			}
+			key := time.Now().UTC().Format(csiConfigHistoryEntryKeyLayout)
+			history.Data[key] = string(resolved)
+			keys = append(keys, key)
+		}
+		for _, key := range keys[:max(0, len(keys)-limit)] {
+			delete(history.Data, key)
+		}
+		return nil
+	}); err != nil {
+		return fmt.Errorf("failed to record csi operator config history: %v", err)
+	}
+	return nil
+}
+
+func (c *OperatorConfigMapReconciler) getCSIConfigHistoryLimit() (int, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[csiConfigHistoryLimitKey])
+	if value == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit < 0 || limit > maxCSIConfigHistoryLimit {
+		return 0, fmt.Errorf("invalid value %q under %s key: must be an integer between 0 and %d",
+			value, csiConfigHistoryLimitKey, maxCSIConfigHistoryLimit)
+	}
+	return limit, nil
+}
+
+// getChangedFields returns the sorted paths, relative to prefix, of the fields that differ between the
+// unstructured objects oldObj and newObj.
+func getChangedFields(oldObj, newObj map[string]any, prefix string) []string {
+	keys := slices.Collect(maps.Keys(oldObj))
+	for key := range newObj {
+		if _, exist := oldObj[key]; !exist {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var changedFields []string
+	for _, key := range keys {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
 		}
+		oldMap, isOldMap := oldObj[key].(map[string]any)
+		newMap, isNewMap := newObj[key].(map[string]any)
+		if isOldMap && isNewMap {
+			changedFields = append(changedFields, getChangedFields(oldMap, newMap, path)...)
+		} else if !equality.Semantic.DeepEqual(oldObj[key], newObj[key]) {
+			changedFields = append(changedFields, path)
+		}
+	}
+	return changedFields
+}
+
+// reportCSIDriverReadiness emits a single event on each deployed csi driver whenever its controller and node
+// plugins become ready. Readiness is tracked in memory, so the event is repeated once after an operator restart.
+func (c *OperatorConfigMapReconciler) reportCSIDriverReadiness() error {
+	if c.csiDriverReadiness == nil {
+		c.csiDriverReadiness = map[string]bool{}
 	}
+	for _, driverName := range []string{templates.RBDDriverName, templates.CephFsDriverName, templates.NfsDriverName} {
+		driver := &csiopv1.Driver{}
+		driver.Name = driverName
+		driver.Namespace = c.OperatorNamespace
+		if err := c.get(driver); kerrors.IsNotFound(err) {
+			delete(c.csiDriverReadiness, driverName)
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to get csi driver %q: %v", driverName, err)
+		}
 
+		isReady, err := c.isCSIDriverReady(driverName)
+		if err != nil {
+			return err
+		}
+		wasReady := c.csiDriverReadiness[driverName]
+		c.csiDriverReadiness[driverName] = isReady
+		if isReady && !wasReady {
+			c.log.Info("csi driver is ready", "driver", driverName)
+			if c.Recorder != nil {
+				c.Recorder.Eventf(driver, nil, corev1.EventTypeNormal, "DriverReady", "Available",
+					"controller and node plugins of csi driver %s are ready", driverName)
+			}
+		}
+	}
 	return nil
 }
 
+func (c *OperatorConfigMapReconciler) isCSIDriverReady(driverName string) (bool, error) {
+	ctrlPlugin := &appsv1.Deployment{}
+	ctrlPlugin.Name = driverName + csiCtrlPluginSuffix
+	ctrlPlugin.Namespace = c.OperatorNamespace
+	if err := c.get(ctrlPlugin); kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get csi controller plugin %q: %v", ctrlPlugin.Name, err)
+	}
+	if ctrlPlugin.Status.ObservedGeneration < ctrlPlugin.Generation ||
+		ctrlPlugin.Status.ReadyReplicas == 0 ||
+		ctrlPlugin.Status.ReadyReplicas < ptr.Deref(ctrlPlugin.Spec.Replicas, 1) {
+		return false, nil
+	}
+
+	nodePlugin := &appsv1.DaemonSet{}
+	nodePlugin.Name = driverName + csiNodePluginSuffix
+	nodePlugin.Namespace = c.OperatorNamespace
+	if err := c.get(nodePlugin); kerrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("failed to get csi node plugin %q: %v", nodePlugin.Name, err)
+	}
+	return nodePlugin.Status.ObservedGeneration >= nodePlugin.Generation &&
+		nodePlugin.Status.DesiredNumberScheduled > 0 &&
+		nodePlugin.Status.NumberReady == nodePlugin.Status.DesiredNumberScheduled, nil
+}
+
 func (c *OperatorConfigMapReconciler) deletionPhase() error {
 	clientsList := &v1alpha1.StorageClientList{}
 	if err := c.list(clientsList, client.Limit(1)); err != nil {
@@ -801,7 +2377,15 @@ func (c *OperatorConfigMapReconciler) deletionPhase() error {
 		return err
 	}
 
-	if err := c.deleteDelegatedCSI(); err != nil {
+	if c.shouldRetainOnUninstall() {
+		c.log.Info("retaining CSI resources on uninstall", "key", retainOnUninstallKey)
+	} else if err := c.deleteDelegatedCSI(); err != nil {
+		return err
+	}
+
+	// the mirrors aren't owned by the operator configmap and aren't garbage collected along with it
+	if err := c.deleteMirroredPrometheusRules(func(*monitoringv1.PrometheusRule) bool { return false }); err != nil {
+		c.log.Error(err, "failed to delete mirrored prometheus rules")
 		return err
 	}
 
@@ -811,46 +2395,329 @@ func (c *OperatorConfigMapReconciler) deletionPhase() error {
 		c.log.Error(err, "failed to delete subscription webhook")
 		return err
 	}
-
+
+	if err := c.deleteConfigMapDefaultingWebhook(); err != nil {
+		c.log.Error(err, "failed to delete configmap defaulting webhook")
+		return err
+	}
+
+	return nil
+}
+
+var errResourcePaused = errors.New("resource is paused")
+
+func (c *OperatorConfigMapReconciler) createOrUpdate(obj client.Object, f controllerutil.MutateFn) error {
+	_, err := c.createOrUpdateWithResult(obj, f)
+	return err
+}
+
+func (c *OperatorConfigMapReconciler) createOrUpdateWithResult(obj client.Object, f controllerutil.MutateFn) (controllerutil.OperationResult, error) {
+	var objDiff string
+	mutateFn := func() error {
+		// obj holds the live state here, paused resources are left untouched
+		if obj.GetLabels()[pausedLabelKey] == strconv.FormatBool(true) {
+			return errResourcePaused
+		}
+		if !c.shouldLogResourceDiffs() {
+			return f()
+		}
+		existing := obj.DeepCopyObject()
+		if err := f(); err != nil {
+			return err
+		}
+		objDiff = diff.Diff(existing, obj)
+		return nil
+	}
+
+	result, err := controllerutil.CreateOrUpdate(c.ctx, c.Client, obj, mutateFn)
+	if errors.Is(err, errResourcePaused) {
+		c.log.Info("skipping paused resource", "kind", reflect.TypeOf(obj).Elem().Name(), "name", obj.GetName(), "label", pausedLabelKey)
+		return controllerutil.OperationResultNone, nil
+	} else if err != nil {
+		return result, err
+	}
+	c.log.Info("successfully created or updated", "operation", result, "name", obj.GetName())
+	if result == controllerutil.OperationResultUpdated {
+		kind := reflect.TypeOf(obj).Elem().Name()
+		ResourceDriftCorrections.WithLabelValues(kind).Inc()
+		if objDiff != "" {
+			c.log.Info("resource diff", "kind", kind, "name", obj.GetName(), "diff", objDiff)
+		}
+	}
+	return result, nil
+}
+
+func (c *OperatorConfigMapReconciler) shouldLogResourceDiffs() bool {
+	logResourceDiffs, _ := utils.ParseBool(c.getOperatorConfigValue(logResourceDiffsKey, "false"))
+	return logResourceDiffs
+}
+
+// own marks obj as managed by the operator configmap. The controller reference is omitted, and removed if
+// present, when RETAIN_ON_UNINSTALL is enabled so that obj survives the removal of the operator.
+func (c *OperatorConfigMapReconciler) own(obj client.Object) error {
+	utils.AddLabel(obj, OwnedByLabelKey, ManagedByLabelValue)
+	if !c.shouldRetainOnUninstall() {
+		return controllerutil.SetControllerReference(c.operatorConfigMap, obj, c.Client.Scheme())
+	}
+
+	isOwned, err := controllerutil.HasOwnerReference(obj.GetOwnerReferences(), c.operatorConfigMap, c.Client.Scheme())
+	if err != nil || !isOwned {
+		return err
+	}
+	return controllerutil.RemoveOwnerReference(c.operatorConfigMap, obj, c.Client.Scheme())
+}
+
+// addManagedLabels stamps the managed-by and owned-by labels on obj, which can't carry an owner reference.
+func addManagedLabels(obj client.Object) {
+	utils.AddLabel(obj, ManagedByLabelKey, ManagedByLabelValue)
+	utils.AddLabel(obj, OwnedByLabelKey, ManagedByLabelValue)
+}
+
+func (c *OperatorConfigMapReconciler) shouldRetainOnUninstall() bool {
+	retainOnUninstall, _ := utils.ParseBool(c.operatorConfigMap.Data[retainOnUninstallKey])
+	return retainOnUninstall
+}
+
+// isOwnedByOperatorConfigMap reports whether obj is controlled by the operator configmap or, lacking a controller,
+// labeled as managed by the operator, as are the resources which can't carry an owner reference and all of them in
+// retain mode.
+func isOwnedByOperatorConfigMap(obj client.Object) bool {
+	if owner := metav1.GetControllerOf(obj); owner != nil {
+		return owner.Kind == "ConfigMap" && owner.Name == operatorConfigMapName
+	}
+	labels := obj.GetLabels()
+	return labels[ManagedByLabelKey] == ManagedByLabelValue || labels[OwnedByLabelKey] == ManagedByLabelValue
+}
+
+// applyLabels merges labels into object meta, overwriting keys that are already defined except for the reserved
+// operator owned keys. The applied keys are recorded under appliedLabelsAnnotationKey so that the keys which are no
+// longer configured are pruned.
+func (c *OperatorConfigMapReconciler) applyLabels(label string, t *metav1.ObjectMeta) {
+	desiredLabels := map[string]string{}
+	labels := strings.Split(label, "\n")
+	// Loop through the lines and extract key-value pairs
+	for _, line := range labels {
+		if len(line) == 0 {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if slices.Contains(reservedLabelKeys, key) {
+			ref := klog.KRef(t.Namespace, t.Name)
+			if c.reportedReservedLabels == nil {
+				c.reportedReservedLabels = map[string]bool{}
+			}
+			if reportKey := ref.String() + "/" + key; !c.reportedReservedLabels[reportKey] {
+				c.reportedReservedLabels[reportKey] = true
+				c.log.Error(nil, "ignoring user supplied label with a reserved key", "key", key, "object", ref)
+			}
+			continue
+		}
+		desiredLabels[key] = strings.TrimSpace(value)
+	}
+
+	for _, key := range strings.Split(t.Annotations[appliedLabelsAnnotationKey], ",") {
+		if _, desired := desiredLabels[key]; !desired && !slices.Contains(reservedLabelKeys, key) {
+			delete(t.Labels, key)
+		}
+	}
+	if len(desiredLabels) == 0 {
+		delete(t.Annotations, appliedLabelsAnnotationKey)
+		return
+	}
+	if t.Labels == nil {
+		t.Labels = map[string]string{}
+	}
+	maps.Copy(t.Labels, desiredLabels)
+	if t.Annotations == nil {
+		t.Annotations = map[string]string{}
+	}
+	t.Annotations[appliedLabelsAnnotationKey] = strings.Join(slices.Sorted(maps.Keys(desiredLabels)), ",")
+}
+
+func (c *OperatorConfigMapReconciler) tracer() trace.Tracer {
+	if c.Tracer == nil {
+		return noopTracer
+	}
+	return c.Tracer
+}
+
+// traceStep runs step in a child span of the current reconcile span named after it, recording its error if any.
+// The context of the reconciler is switched to the child span for the duration of step.
+func (c *OperatorConfigMapReconciler) traceStep(name string, step func() error) error {
+	parentCtx := c.ctx
+	ctx, span := c.tracer().Start(parentCtx, name)
+	defer span.End()
+
+	c.ctx = ctx
+	defer func() { c.ctx = parentCtx }()
+	err := step()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
+
+// reconcileWithCircuitBreaker runs reconcileFn for resource, tracking its consecutive failures. Once they reach
+// circuitBreakerThreshold the breaker opens: the failure is reported as a Degraded event on the operator configmap
+// and swallowed, and the resource is skipped until it's retried circuitBreakerRetryInterval later, so that a
+// permanent failure doesn't hot loop the reconcile while the remaining resources are still reconciled. A success
+// closes the breaker.
+func (c *OperatorConfigMapReconciler) reconcileWithCircuitBreaker(resource string, reconcileFn func() error) error {
+	if c.resourceFailures == nil {
+		c.resourceFailures = map[string]int{}
+		c.resourceRetryTimes = map[string]time.Time{}
+	}
+	if retryTime, open := c.resourceRetryTimes[resource]; open && time.Now().Before(retryTime) {
+		c.circuitBreakerOpen = true
+		c.log.Info("circuit breaker of the resource is open, skipping it", "resource", resource, "retryTime", retryTime)
+		return nil
+	}
+
+	err := reconcileFn()
+	if err == nil {
+		if c.resourceFailures[resource] >= circuitBreakerThreshold {
+			c.log.Info("resource reconciled successfully, closing its circuit breaker", "resource", resource)
+		}
+		delete(c.resourceFailures, resource)
+		delete(c.resourceRetryTimes, resource)
+		return nil
+	}
+
+	c.resourceFailures[resource]++
+	failures := c.resourceFailures[resource]
+	if failures < circuitBreakerThreshold {
+		return err
+	}
+	c.circuitBreakerOpen = true
+	c.resourceRetryTimes[resource] = time.Now().Add(circuitBreakerRetryInterval)
+	if failures == circuitBreakerThreshold {
+		c.log.Error(err, "resource keeps failing, opening its circuit breaker", "resource", resource, "failures", failures)
+		if c.Recorder != nil {
+			c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeWarning, "Degraded", "Reconcile",
+				"%s failed %d consecutive reconciles, retrying every %s: %v", resource, failures,
+				circuitBreakerRetryInterval, err)
+		}
+	} else {
+		c.log.Error(err, "resource still failing, keeping its circuit breaker open", "resource", resource,
+			"failures", failures)
+	}
+	return nil
+}
+
+// getShortestRequeueAfter returns the shorter of the requeue intervals, a zero interval meaning no requeue.
+func getShortestRequeueAfter(requeueAfter, other time.Duration) time.Duration {
+	if requeueAfter == 0 {
+		return other
+	}
+	return min(requeueAfter, other)
+}
+
+// loadConditions reads the conditions previously reported in the status configmap. Unreadable conditions are
+// discarded and reported afresh.
+func (c *OperatorConfigMapReconciler) loadConditions() {
+	c.conditions = nil
+	status := &corev1.ConfigMap{}
+	status.Name = operatorStatusConfigMapName
+	status.Namespace = c.OperatorNamespace
+	if err := c.get(status); kerrors.IsNotFound(err) {
+		return
+	} else if err != nil {
+		c.log.Error(err, "failed to get the reported conditions, resetting them", "configMap", klog.KObj(status))
+		return
+	}
+	if val := status.Data[statusConditionsKey]; val != "" {
+		if err := json.Unmarshal([]byte(val), &c.conditions); err != nil {
+			c.log.Error(err, "failed to parse the reported conditions, resetting them", "key", statusConditionsKey)
+			c.conditions = nil
+		}
+	}
+}
+
+// reportRequiredPermissions reviews the requiredPermissions of the operator service account and reports the
+// missing ones in the PermissionsAvailable condition, persisted right away so that it's visible even when the
+// missing permissions fail the rest of the reconcile.
+func (c *OperatorConfigMapReconciler) reportRequiredPermissions() error {
+	var missing []string
+	for _, permission := range requiredPermissions {
+		review := &authorizationv1.SelfSubjectAccessReview{}
+		review.Spec.ResourceAttributes = permission.ResourceAttributes.DeepCopy()
+		if permission.namespaced {
+			review.Spec.ResourceAttributes.Namespace = c.OperatorNamespace
+		}
+		if err := c.Create(c.ctx, review); err != nil {
+			return fmt.Errorf("failed to review %s %s.%s access: %v", permission.Verb, permission.Resource, permission.Group, err)
+		}
+		if !review.Status.Allowed {
+			missing = append(missing, fmt.Sprintf("%s %s.%s", permission.Verb, permission.Resource, permission.Group))
+		}
+	}
+
+	if len(missing) > 0 {
+		c.log.Error(nil, "the operator is missing required permissions, check its ClusterRole", "missing", missing)
+		c.setCondition(conditionTypePermissionsAvailable, metav1.ConditionFalse, "AccessDenied",
+			fmt.Sprintf("missing permissions: %s", strings.Join(missing, ", ")))
+	} else {
+		c.setCondition(conditionTypePermissionsAvailable, metav1.ConditionTrue, "AccessAllowed", "")
+	}
+	if err := c.reportConditions(); err != nil {
+		return err
+	}
+	c.permissionsChecked = true
 	return nil
 }
 
-func (c *OperatorConfigMapReconciler) createOrUpdate(obj client.Object, f controllerutil.MutateFn) error {
-	_, err := c.createOrUpdateWithResult(obj, f)
-	return err
+// setCondition records a condition of the given type, keeping its lastTransitionTime unless the status changes.
+// The condition is persisted by reportConditions. It returns whether the condition changed.
+func (c *OperatorConfigMapReconciler) setCondition(conditionType string, status metav1.ConditionStatus, reason, message string) bool {
+	return meta.SetStatusCondition(&c.conditions, metav1.Condition{
+		Type:    conditionType,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
 }
 
-func (c *OperatorConfigMapReconciler) createOrUpdateWithResult(obj client.Object, f controllerutil.MutateFn) (controllerutil.OperationResult, error) {
-	result, err := controllerutil.CreateOrUpdate(c.ctx, c.Client, obj, f)
-	if err != nil {
-		return result, err
-	}
-	c.log.Info("successfully created or updated", "operation", result, "name", obj.GetName())
-	return result, nil
+// getCondition returns the condition of the given type, or nil if it isn't reported.
+func (c *OperatorConfigMapReconciler) getCondition(conditionType string) *metav1.Condition {
+	return meta.FindStatusCondition(c.conditions, conditionType)
 }
 
-func (c *OperatorConfigMapReconciler) own(obj client.Object) error {
-	return controllerutil.SetControllerReference(c.operatorConfigMap, obj, c.Client.Scheme())
+// reportConditions writes the conditions into the statusConditionsKey of the status configmap, owned by the
+// operator configmap, if they changed.
+func (c *OperatorConfigMapReconciler) reportConditions() error {
+	conditions, err := json.Marshal(c.conditions)
+	if err != nil {
+		return fmt.Errorf("failed to marshal conditions: %v", err)
+	}
+	status := &corev1.ConfigMap{}
+	status.Name = operatorStatusConfigMapName
+	status.Namespace = c.OperatorNamespace
+	return c.createOrUpdate(status, func() error {
+		if status.Data == nil {
+			status.Data = map[string]string{}
+		}
+		status.Data[statusConditionsKey] = string(conditions)
+		return c.own(status)
+	})
 }
 
-// applyLabels adds labels to object meta, overwriting keys that are already defined.
-func applyLabels(label string, t *metav1.ObjectMeta) {
-	// Create a map to store the configuration
-	promLabel := make(map[string]string)
-
-	labels := strings.Split(label, "\n")
-	// Loop through the lines and extract key-value pairs
-	for _, line := range labels {
-		if len(line) == 0 {
-			continue
+// ensureConsolePluginOrDegrade deploys the client console, reporting a failure as a ConsolePluginDegraded event on
+// the operator configmap. The error is returned for the caller to retry once the remaining steps are done.
+func (c *OperatorConfigMapReconciler) ensureConsolePluginOrDegrade() error {
+	err := c.ensureConsolePlugin()
+	if err != nil {
+		c.log.Error(err, "unable to deploy client console, continuing with the remaining steps")
+		if c.Recorder != nil {
+			c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeWarning, "ConsolePluginDegraded", "Deploy",
+				"unable to deploy client console: %v", err)
 		}
-		parts := strings.SplitN(line, ":", 2)
-		key := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		promLabel[key] = value
 	}
-
-	t.Labels = promLabel
+	return err
 }
 
 func (c *OperatorConfigMapReconciler) ensureConsolePlugin() error {
@@ -889,14 +2756,38 @@ func (c *OperatorConfigMapReconciler) ensureConsolePlugin() error {
 	if nginxConfigMapResult == controllerutil.OperationResultCreated || nginxConfigMapResult == controllerutil.OperationResultUpdated {
 		c.log.Info("nginx ConfigMap updated with new config, console pod will reload nginx automatically")
 	}
+	c.reportConsoleProxyTLSVerify()
+
+	listenPort, servicePort, err := c.getConsolePorts()
+	if err != nil {
+		return err
+	}
+
+	// the console deployment is installed from the CSV, OLM only rewrites it when installing a new CSV and doesn't
+	// revert the fields patched here in the meantime. Once it does, the deployment watch brings them back, so the
+	// patches stay confined to the port, the pod template annotation and the replicas.
+	if err := c.reconcileConsoleListenPort(listenPort); err != nil {
+		c.log.Error(err, "failed to set the listen port of the console deployment")
+		return err
+	}
+
+	if err := c.annotateConsoleNginxRootConfHash(nginxConfigMap.Data[nginxRootConfKey]); err != nil {
+		c.log.Error(err, "failed to annotate the console deployment with the nginx config hash")
+		return err
+	}
 
-	consoleService := console.GetService(c.ConsolePort, c.OperatorNamespace)
+	if err := c.reconcileConsoleReplicas(); err != nil {
+		c.log.Error(err, "failed to scale the console deployment")
+		return err
+	}
+
+	consoleService := console.GetService(listenPort, servicePort, c.OperatorNamespace)
 
 	err = c.createOrUpdate(consoleService, func() error {
 		if err := controllerutil.SetControllerReference(c.consoleDeployment, consoleService, c.Scheme); err != nil {
 			return err
 		}
-		console.GetService(c.ConsolePort, c.OperatorNamespace).DeepCopyInto(consoleService)
+		console.GetService(listenPort, servicePort, c.OperatorNamespace).DeepCopyInto(consoleService)
 		return nil
 	})
 
@@ -905,11 +2796,11 @@ func (c *OperatorConfigMapReconciler) ensureConsolePlugin() error {
 		return err
 	}
 
-	consolePlugin := console.GetConsolePlugin(c.ConsolePort, c.OperatorNamespace)
+	consolePlugin := console.GetConsolePlugin(servicePort, c.OperatorNamespace, c.shouldForwardConsoleProxyAuth())
 	err = c.createOrUpdate(consolePlugin, func() error {
 		// preserve the resourceVersion of the consolePlugin
 		resourceVersion := consolePlugin.ResourceVersion
-		console.GetConsolePlugin(c.ConsolePort, c.OperatorNamespace).DeepCopyInto(consolePlugin)
+		console.GetConsolePlugin(servicePort, c.OperatorNamespace, c.shouldForwardConsoleProxyAuth()).DeepCopyInto(consolePlugin)
 		consolePlugin.ResourceVersion = resourceVersion
 		return nil
 	})
@@ -922,15 +2813,119 @@ func (c *OperatorConfigMapReconciler) ensureConsolePlugin() error {
 	return nil
 }
 
+// reconcileConsoleListenPort patches the console.ListenPortName container port of the console container, and its
+// liveness probe unless it refers to the port by name, to listenPort so that they follow the port nginx is
+// configured to listen on. Other ports, e.g. of a sidecar proxy, are left alone.
+func (c *OperatorConfigMapReconciler) reconcileConsoleListenPort(listenPort int32) error {
+	patch := client.MergeFrom(c.consoleDeployment.DeepCopy())
+	changed := false
+	containers := c.consoleDeployment.Spec.Template.Spec.Containers
+	for i := range containers {
+		container := &containers[i]
+		if container.Name != console.DeploymentName {
+			continue
+		}
+		for j := range container.Ports {
+			if container.Ports[j].Name == console.ListenPortName && container.Ports[j].ContainerPort != listenPort {
+				container.Ports[j].ContainerPort = listenPort
+				changed = true
+			}
+		}
+		if probe := container.LivenessProbe; probe != nil && probe.HTTPGet != nil &&
+			probe.HTTPGet.Port.Type == intstr.Int && probe.HTTPGet.Port.IntValue() != int(listenPort) {
+			probe.HTTPGet.Port = intstr.FromInt32(listenPort)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	if err := c.Patch(c.ctx, c.consoleDeployment, patch); err != nil {
+		return err
+	}
+	c.log.Info("console listen port changed, rolling the console pods", "port", listenPort)
+	return nil
+}
+
+// annotateConsoleNginxRootConfHash sets the hash of the nginx root config on the console pod template. The console
+// pods only hot reload the per client proxy configs, so a changed root config, e.g. the listen port, rolls them.
+func (c *OperatorConfigMapReconciler) annotateConsoleNginxRootConfHash(rootConf string) error {
+	rootConfHash := utils.GetMD5Hash(rootConf)
+	if c.consoleDeployment.Spec.Template.Annotations[nginxRootConfHashAnnotationKey] == rootConfHash {
+		return nil
+	}
+	patch := client.MergeFrom(c.consoleDeployment.DeepCopy())
+	metav1.SetMetaDataAnnotation(&c.consoleDeployment.Spec.Template.ObjectMeta, nginxRootConfHashAnnotationKey, rootConfHash)
+	if err := c.Patch(c.ctx, c.consoleDeployment, patch); err != nil {
+		return err
+	}
+	c.log.Info("nginx root config changed, rolling the console pods")
+	return nil
+}
+
+// reconcileConsoleReplicas patches the replica count of the console deployment to the value of
+// consolePluginReplicasKey. The deployment isn't touched while the key is unset.
+func (c *OperatorConfigMapReconciler) reconcileConsoleReplicas() error {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[consolePluginReplicasKey])
+	if value == "" {
+		return nil
+	}
+	replicas, err := strconv.ParseInt(value, 10, 32)
+	if err != nil || replicas < 0 {
+		return fmt.Errorf("invalid value %q under %s key: must be a non-negative integer", value, consolePluginReplicasKey)
+	}
+	if c.consoleDeployment.Spec.Replicas != nil && *c.consoleDeployment.Spec.Replicas == int32(replicas) {
+		return nil
+	}
+	patch := client.MergeFrom(c.consoleDeployment.DeepCopy())
+	c.consoleDeployment.Spec.Replicas = ptr.To(int32(replicas))
+	if err := c.Patch(c.ctx, c.consoleDeployment, patch); err != nil {
+		return err
+	}
+	c.log.Info("scaled the console deployment", "replicas", replicas)
+	return nil
+}
+
+// getConsolePorts returns the port nginx listens on and the port of the service registered with the
+// ConsolePlugin, both defaulting to the console port the operator is started with.
+func (c *OperatorConfigMapReconciler) getConsolePorts() (listenPort, servicePort int32, err error) {
+	parsePort := func(key string) (int32, error) {
+		value := strings.TrimSpace(c.operatorConfigMap.Data[key])
+		if value == "" {
+			return c.ConsolePort, nil
+		}
+		port, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || port < 1 || port > 65535 {
+			return 0, fmt.Errorf("invalid value %q under %s key: must be a port number", value, key)
+		}
+		return int32(port), nil
+	}
+	if listenPort, err = parsePort(consoleListenPortKey); err != nil {
+		return 0, 0, err
+	}
+	if servicePort, err = parsePort(consoleServicePortKey); err != nil {
+		return 0, 0, err
+	}
+	return listenPort, servicePort, nil
+}
+
 func (c *OperatorConfigMapReconciler) buildDesiredNginxDataWithProxies() (map[string]string, error) {
+	listenPort, _, err := c.getConsolePorts()
+	if err != nil {
+		return nil, err
+	}
+	rootConf, err := console.GetNginxRootConf(listenPort)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build nginx root config: %w", err)
+	}
 	out := map[string]string{
 		// Root config is mandatory for nginx to start. Proxy configs (per client) are optional.
-		"nginx.conf": console.GetNginxRootConf(),
+		nginxRootConfKey: rootConf,
 	}
 
 	if c.operatorConfigMap.Data != nil {
 		if disableS3EndpointProxyValue, ok := c.operatorConfigMap.Data[disableS3EndpointProxyKey]; ok {
-			disableS3EndpointProxy, err := strconv.ParseBool(disableS3EndpointProxyValue)
+			disableS3EndpointProxy, err := utils.ParseBool(disableS3EndpointProxyValue)
 			if err != nil {
 				return nil, fmt.Errorf("unsupported value under disableS3EndpointProxy key: %w", err)
 			}
@@ -941,7 +2936,7 @@ func (c *OperatorConfigMapReconciler) buildDesiredNginxDataWithProxies() (map[st
 		}
 	}
 
-	err := c.computeDesiredProxyConfigByKey(out)
+	err = c.computeDesiredProxyConfigByKey(out)
 	return out, err
 }
 
@@ -1011,6 +3006,7 @@ func (c *OperatorConfigMapReconciler) buildS3EndpointProxyConfigForClient(unique
 	// Processing endpoints in a fixed order so the generated nginx config "string" wouldn't change unless the actual endpoint data changed.
 	// This avoids unnecessary ConfigMap updates and nginx reloads.
 	sort.Strings(exposeAsKeys)
+	insecureSkipVerify := c.shouldSkipConsoleProxyTLSVerify()
 	for _, exposeAs := range exposeAsKeys {
 		cfg := endpoints[exposeAs]
 		endpointURL := strings.TrimSpace(cfg.EndpointURL)
@@ -1045,6 +3041,9 @@ func (c *OperatorConfigMapReconciler) buildS3EndpointProxyConfigForClient(unique
 			endpointURL,
 			endpointHost,
 			certsPath,
+			c.shouldForwardConsoleProxyAuth(),
+			insecureSkipVerify,
+			c.shouldDisableConsoleProxyCache(),
 		)
 		if err != nil {
 			return "", fmt.Errorf("failed to build proxy config for %q: %w", exposeAs, err)
@@ -1054,9 +3053,41 @@ func (c *OperatorConfigMapReconciler) buildS3EndpointProxyConfigForClient(unique
 	return sb.String(), nil
 }
 
+func (c *OperatorConfigMapReconciler) shouldForwardConsoleProxyAuth() bool {
+	forwardAuth, _ := utils.ParseBool(c.getOperatorConfigValue(consoleProxyForwardAuthKey, "false"))
+	return forwardAuth
+}
+
+// reportConsoleProxyTLSVerify reports in the ConsoleProxyTLSVerified condition, and a warning event when it changes,
+// whether the console proxy verifies the certificates of the proxied s3 endpoints.
+func (c *OperatorConfigMapReconciler) reportConsoleProxyTLSVerify() {
+	if !c.shouldSkipConsoleProxyTLSVerify() {
+		c.setCondition(conditionTypeConsoleProxyTLSVerified, metav1.ConditionTrue, "TLSVerified", "")
+		return
+	}
+	message := fmt.Sprintf("TLS verification of the proxied s3 endpoints is disabled by %s", consoleProxyInsecureSkipVerifyKey)
+	if !c.setCondition(conditionTypeConsoleProxyTLSVerified, metav1.ConditionFalse, "InsecureSkipVerify", message) {
+		return
+	}
+	c.log.Info("TLS verification of the proxied s3 endpoints is disabled", "key", consoleProxyInsecureSkipVerifyKey)
+	if c.Recorder != nil {
+		c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeWarning, "ConsoleProxyInsecureSkipVerify", "Deploy", "%s", message)
+	}
+}
+
+func (c *OperatorConfigMapReconciler) shouldSkipConsoleProxyTLSVerify() bool {
+	insecureSkipVerify, _ := utils.ParseBool(c.getOperatorConfigValue(consoleProxyInsecureSkipVerifyKey, "false"))
+	return insecureSkipVerify
+}
+
+func (c *OperatorConfigMapReconciler) shouldDisableConsoleProxyCache() bool {
+	disableCache, _ := utils.ParseBool(c.getOperatorConfigValue(consoleProxyDisableCacheKey, "false"))
+	return disableCache
+}
+
 func (c *OperatorConfigMapReconciler) shouldGenerateRBDOmapInfo() bool {
-	valAsString := strings.ToLower(c.operatorConfigMap.Data[generateRbdOMapInfoKey])
-	return valAsString == strconv.FormatBool(true)
+	generateOmapInfo, _ := utils.ParseBool(c.operatorConfigMap.Data[generateRbdOMapInfoKey])
+	return generateOmapInfo
 }
 
 func (c *OperatorConfigMapReconciler) get(obj client.Object, opts ...client.GetOption) error {
@@ -1064,11 +3095,31 @@ func (c *OperatorConfigMapReconciler) get(obj client.Object, opts ...client.GetO
 }
 
 func (c *OperatorConfigMapReconciler) reconcileSubscriptionValidatingWebhook() error {
+	operations, err := c.getSubscriptionWebhookOperations()
+	if err != nil {
+		return err
+	}
+
 	whConfig := &admrv1.ValidatingWebhookConfiguration{}
 	whConfig.Name = templates.SubscriptionWebhookName
 
 	// TODO (lgangava): after change to configmap controller, need to remove webhook during deletion
-	err := c.createOrUpdate(whConfig, func() error {
+	endpointsReady, err := c.hasReadyWebhookEndpoints()
+	if err != nil {
+		return err
+	}
+	if endpointsReady {
+		c.setCondition(conditionTypeWebhookEndpointsReady, metav1.ConditionTrue, "EndpointsReady", "")
+	} else if c.setCondition(conditionTypeWebhookEndpointsReady, metav1.ConditionFalse, "WebhookEndpointsNotReady",
+		fmt.Sprintf("service %s backing the subscription webhook has no ready endpoints", templates.WebhookServiceName)) {
+		c.log.Info("webhook service has no ready endpoints, deferring the Fail failure policy", "service", templates.WebhookServiceName)
+		if c.Recorder != nil {
+			c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeWarning, "WebhookEndpointsNotReady", "Validate",
+				"service %s backing the subscription webhook has no ready endpoints", templates.WebhookServiceName)
+		}
+	}
+
+	err = c.createOrUpdate(whConfig, func() error {
 
 		// openshift fills in the ca on finding this annotation
 		whConfig.Annotations = map[string]string{
@@ -1088,6 +3139,12 @@ func (c *OperatorConfigMapReconciler) reconcileSubscriptionValidatingWebhook() e
 		templates.SubscriptionValidatingWebhook.DeepCopyInto(wh)
 
 		wh.Name = whConfig.Name
+		// intercept the configured operations, if any, instead of the template defaults
+		if len(operations) > 0 {
+			for i := range wh.Rules {
+				wh.Rules[i].Operations = operations
+			}
+		}
 		// only send requests received from own namespace
 		wh.NamespaceSelector = &metav1.LabelSelector{
 			MatchLabels: map[string]string{
@@ -1104,6 +3161,12 @@ func (c *OperatorConfigMapReconciler) reconcileSubscriptionValidatingWebhook() e
 		wh.ClientConfig.CABundle = caBundle
 		// send request to the service running in own namespace
 		wh.ClientConfig.Service.Namespace = c.OperatorNamespace
+		// the api server can't call the webhook until openshift injects the CA bundle, which happens asynchronously
+		c.webhookCAPending = len(caBundle) == 0
+		// don't block subscriptions while the webhook can't be reached
+		if !endpointsReady || c.webhookCAPending {
+			wh.FailurePolicy = ptr.To(admrv1.Ignore)
+		}
 
 		return nil
 	})
@@ -1112,10 +3175,61 @@ func (c *OperatorConfigMapReconciler) reconcileSubscriptionValidatingWebhook() e
 		return err
 	}
 
+	if c.webhookCAPending {
+		c.log.Info("waiting for the ca bundle to be injected into the subscription webhook, deferring the Fail failure policy",
+			"webhook", whConfig.Name)
+	}
 	c.log.Info("successfully registered validating webhook")
 	return nil
 }
 
+// hasReadyWebhookEndpoints reports whether the webhook service has at least one ready endpoint.
+func (c *OperatorConfigMapReconciler) hasReadyWebhookEndpoints() (bool, error) {
+	endpointSlices := &discoveryv1.EndpointSliceList{}
+	if err := c.list(
+		endpointSlices,
+		client.InNamespace(c.OperatorNamespace),
+		client.MatchingLabels{discoveryv1.LabelServiceName: templates.WebhookServiceName},
+	); err != nil {
+		return false, fmt.Errorf("failed to list endpoint slices of service %q: %v", templates.WebhookServiceName, err)
+	}
+	for i := range endpointSlices.Items {
+		for _, endpoint := range endpointSlices.Items[i].Endpoints {
+			if ptr.Deref(endpoint.Conditions.Ready, true) {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// getSubscriptionWebhookOperations returns the admission operations configured for the subscription
+// webhook, or nil if the operations from the webhook template should be used.
+func (c *OperatorConfigMapReconciler) getSubscriptionWebhookOperations() ([]admrv1.OperationType, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[subscriptionWebhookOperationsKey])
+	if value == "" {
+		return nil, nil
+	}
+
+	supportedOperations := []admrv1.OperationType{admrv1.Create, admrv1.Update, admrv1.Delete}
+	var operations []admrv1.OperationType
+	for token := range strings.SplitSeq(value, ",") {
+		token = strings.TrimSpace(token)
+		if token == "" {
+			continue
+		}
+		operation := admrv1.OperationType(strings.ToUpper(token))
+		if !slices.Contains(supportedOperations, operation) {
+			return nil, fmt.Errorf("unsupported operation %q under %s key, supported values are %v",
+				token, subscriptionWebhookOperationsKey, supportedOperations)
+		}
+		if !slices.Contains(operations, operation) {
+			operations = append(operations, operation)
+		}
+	}
+	return operations, nil
+}
+
 func (c *OperatorConfigMapReconciler) reconcileCSIAddonsOperatorSubscription() error {
 	addonsSubscription, err := getSubscriptionByPackageName(c.ctx, c.Client, c.OperatorNamespace, "odf-csi-addons-operator")
 	if kerrors.IsNotFound(err) {
@@ -1178,6 +3292,84 @@ func (c *OperatorConfigMapReconciler) reconcileODFSnapshotterSubscription() erro
 	return nil
 }
 
+// deleteConfigMapDefaultingWebhook removes the configmap defaulting webhook, without a delete request once it's gone.
+func (c *OperatorConfigMapReconciler) deleteConfigMapDefaultingWebhook() error {
+	whConfig := &admrv1.MutatingWebhookConfiguration{}
+	whConfig.Name = templates.ConfigMapDefaultingWebhookName
+	if err := c.get(whConfig); kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get configmap defaulting webhook: %v", err)
+	}
+	if err := c.delete(whConfig); err != nil {
+		return fmt.Errorf("failed to delete configmap defaulting webhook: %v", err)
+	}
+	c.log.Info("configmap defaulting webhook deleted", "webhook", whConfig.Name)
+	return nil
+}
+
+// reconcileConfigMapDefaultingWebhook registers the mutating webhook filling in the documented defaults of
+// this configmap when enabled via the DEPLOY_CONFIGMAP_DEFAULTING_WEBHOOK key, and removes it otherwise.
+func (c *OperatorConfigMapReconciler) reconcileConfigMapDefaultingWebhook() error {
+	whConfig := &admrv1.MutatingWebhookConfiguration{}
+	whConfig.Name = templates.ConfigMapDefaultingWebhookName
+
+	deployWebhook, err := utils.ParseBool(cmp.Or(c.operatorConfigMap.Data[deployConfigMapDefaultingWebhookKey], "false"))
+	if err != nil {
+		return fmt.Errorf("failed to parse value for %q in operator configmap as a boolean: %v", deployConfigMapDefaultingWebhookKey, err)
+	}
+	if !deployWebhook {
+		return c.deleteConfigMapDefaultingWebhook()
+	}
+
+	if err := c.reconcileWebhookService(); err != nil {
+		return err
+	}
+
+	err = c.createOrUpdate(whConfig, func() error {
+		// openshift fills in the ca on finding this annotation
+		whConfig.Annotations = map[string]string{
+			"service.beta.openshift.io/inject-cabundle": "true",
+		}
+
+		var caBundle []byte
+		if len(whConfig.Webhooks) == 0 {
+			whConfig.Webhooks = make([]admrv1.MutatingWebhook, 1)
+		} else {
+			// do not mutate CA bundle that was injected by openshift
+			caBundle = whConfig.Webhooks[0].ClientConfig.CABundle
+		}
+
+		wh := &whConfig.Webhooks[0]
+		templates.ConfigMapDefaultingMutatingWebhook.DeepCopyInto(wh)
+
+		wh.Name = whConfig.Name
+		// only send requests received from own namespace
+		wh.NamespaceSelector = &metav1.LabelSelector{
+			MatchLabels: map[string]string{
+				"kubernetes.io/metadata.name": c.OperatorNamespace,
+			},
+		}
+		// configmaps can't be selected by name via object selector
+		wh.MatchConditions = []admrv1.MatchCondition{
+			{
+				Name:       "operator-configmap",
+				Expression: fmt.Sprintf("object.metadata.name == %q", operatorConfigMapName),
+			},
+		}
+		wh.ClientConfig.CABundle = caBundle
+		wh.ClientConfig.Service.Namespace = c.OperatorNamespace
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	c.log.Info("successfully registered configmap defaulting webhook")
+	return nil
+}
+
 func (c *OperatorConfigMapReconciler) reconcileWebhookService() error {
 	svc := &corev1.Service{}
 	svc.Name = templates.WebhookServiceName
@@ -1234,46 +3426,235 @@ func getSubscriptionByPackageName(
 	} else if len(subList.Items) > 1 {
 		return nil, fmt.Errorf("more than one subscription found for %v", pkgName)
 	}
-
-	return &subList.Items[0], nil
+
+	return &subList.Items[0], nil
+}
+
+// operatorConfigMapDefaults are the documented values assumed for the unset keys of the operator configmap.
+var operatorConfigMapDefaults = map[string]string{
+	disableVersionChecksKey:           "false",
+	disableInstallPlanAutoApprovalKey: "false",
+	generateRbdOMapInfoKey:            "false",
+	enableRbdDriverKey:                "false",
+	enableCephFsDriverKey:             "false",
+	enableNfsDriverKey:                "false",
+	disableS3EndpointProxyKey:         "false",
+	AlertPollIntervalKey:              alert.DefaultPollInterval.String(),
+}
+
+// OperatorConfigMapDefaults returns a copy of the documented values assumed for the unset keys of the operator
+// configmap.
+func OperatorConfigMapDefaults() map[string]string {
+	return maps.Clone(operatorConfigMapDefaults)
+}
+
+// getClientOperatorSubscription returns the subscription of this operator. It is looked up in the operator
+// namespace first and then across all namespaces, as OLM places it according to the OperatorGroup.
+func getClientOperatorSubscription(
+	ctx context.Context,
+	kubeClient client.Client,
+	operatorNamespace string,
+) (*opv1a1.Subscription, error) {
+	subscription, err := getSubscriptionByPackageName(ctx, kubeClient, operatorNamespace, clientOperatorPackageName)
+	if kerrors.IsNotFound(err) {
+		subscription, err = getSubscriptionByPackageName(ctx, kubeClient, metav1.NamespaceAll, clientOperatorPackageName)
+	}
+	return subscription, err
+}
+
+// unlabelClientOperatorSubscription removes the label matched by the subscription webhook object selector from the
+// client operator subscription once the webhook is disabled, so that a stale label doesn't linger.
+func (c *OperatorConfigMapReconciler) unlabelClientOperatorSubscription() error {
+	subscription, err := getClientOperatorSubscription(c.ctx, c.Client, c.OperatorNamespace)
+	if kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if subscription.Labels[subscriptionLabelKey] != subscriptionLabelValue {
+		return nil
+	}
+	utils.RemoveLabel(subscription, subscriptionLabelKey)
+	return c.update(subscription)
+}
+
+func (c *OperatorConfigMapReconciler) getDesiredSubscriptionChannel(storageClients *v1alpha1.StorageClientList) (string, error) {
+
+	var desiredChannel string
+	for idx := range storageClients.Items {
+		// empty if annotation doesn't exist or else gets desired channel
+		channel := storageClients.
+			Items[idx].
+			GetAnnotations()[utils.DesiredSubscriptionChannelAnnotationKey]
+		// skip clients with no/empty desired channel annotation
+		if channel != "" {
+			// check if we already established a desired channel
+			if desiredChannel == "" {
+				desiredChannel = channel
+			}
+			// check for agreement between clients
+			if channel != desiredChannel {
+				desiredChannel = ""
+				// two clients didn't agree for a same channel and no need to continue further
+				break
+			}
+		}
+	}
+
+	clientSubscription, err := getClientOperatorSubscription(c.ctx, c.Client, c.OperatorNamespace)
+	if err != nil {
+		return "", err
+	}
+
+	if desiredChannel != clientSubscription.Spec.Channel {
+		// this condition happens when the provider is ahead of the client(s) but client operator upgrade is not
+		// triggered, we shouldn't error out here but go ahead and complete the current reconcile
+		c.log.Info("Skipping subs update of dependencies: client operator Subscription channel needs an update")
+		return "", nil
+	}
+
+	return desiredChannel, nil
+}
+
+// reconcileCSIImageSetOverrides applies the CSI_REGISTRAR_IMAGE and CSI_RBD_PLUGIN_IMAGE overrides to copies of the
+// imageset imageSetName, returning the imagesets for the driver spec defaults and for the rbd driver. An empty rbd
+// imageset means the rbd driver uses the defaults. The copies are removed once their overrides are unset.
+func (c *OperatorConfigMapReconciler) reconcileCSIImageSetOverrides(imageSetName string) (string, string, error) {
+	getImage := func(key string) (string, error) {
+		image := strings.TrimSpace(c.operatorConfigMap.Data[key])
+		if strings.ContainsAny(image, " \t\n") {
+			return "", fmt.Errorf("invalid value %q under %s key: must be an image reference", image, key)
+		}
+		return image, nil
+	}
+	registrarImage, err := getImage(csiRegistrarImageKey)
+	if err != nil {
+		return "", "", err
+	}
+	rbdPluginImage, err := getImage(csiRBDPluginImageKey)
+	if err != nil {
+		return "", "", err
+	}
+
+	imageSet := &corev1.ConfigMap{}
+	imageSet.Name = imageSetName
+	imageSet.Namespace = c.OperatorNamespace
+	if registrarImage != "" || rbdPluginImage != "" {
+		if err := c.get(imageSet); err != nil {
+			return "", "", fmt.Errorf("failed to get imageset configmap %q: %v", imageSetName, err)
+		}
+	}
+	reconcileOverride := func(name string, overrides map[string]string) (string, error) {
+		override := &corev1.ConfigMap{}
+		override.Name = name
+		override.Namespace = c.OperatorNamespace
+		if len(overrides) == 0 {
+			return "", c.delete(override)
+		}
+		if err := c.createOrUpdate(override, func() error {
+			override.Data = maps.Clone(imageSet.Data)
+			if override.Data == nil {
+				override.Data = map[string]string{}
+			}
+			maps.Copy(override.Data, overrides)
+			return c.own(override)
+		}); err != nil {
+			return "", fmt.Errorf("failed to reconcile imageset configmap %q: %v", name, err)
+		}
+		return name, nil
+	}
+
+	overrides := map[string]string{}
+	if registrarImage != "" {
+		overrides[imageSetRegistrarKey] = registrarImage
+	}
+	defaultsImageSetName, err := reconcileOverride(csiImageSetConfigMapName, overrides)
+	if err != nil {
+		return "", "", err
+	}
+	if rbdPluginImage != "" {
+		overrides[imageSetPluginKey] = rbdPluginImage
+	} else {
+		overrides = nil
+	}
+	rbdImageSetName, err := reconcileOverride(csiRBDImageSetConfigMapName, overrides)
+	if err != nil {
+		return "", "", err
+	}
+	return cmp.Or(defaultsImageSetName, imageSetName), rbdImageSetName, nil
 }
 
-func (c *OperatorConfigMapReconciler) getDesiredSubscriptionChannel(storageClients *v1alpha1.StorageClientList) (string, error) {
+// reportCSIImageUpgradePlan compares the images of the containers in the live CSI controller and node plugins with
+// the images of the imagesets they are about to be configured with, and logs the pending changes along with an
+// event before ceph-csi-operator rolls them out. rbdImageSetName takes precedence for the rbd driver when set.
+// The plan is reported once until the images of the imagesets change.
+func (c *OperatorConfigMapReconciler) reportCSIImageUpgradePlan(imageSetName, rbdImageSetName string) error {
+	getImages := func(name string) (map[string]string, error) {
+		imageSet := &corev1.ConfigMap{}
+		imageSet.Name = name
+		imageSet.Namespace = c.OperatorNamespace
+		if err := c.get(imageSet); err != nil {
+			return nil, fmt.Errorf("failed to get imageset configmap %q: %v", name, err)
+		}
+		return imageSet.Data, nil
+	}
+	defaultImages, err := getImages(imageSetName)
+	if err != nil {
+		return err
+	}
+	rbdImages := defaultImages
+	if rbdImageSetName != "" {
+		if rbdImages, err = getImages(rbdImageSetName); err != nil {
+			return err
+		}
+	}
 
-	var desiredChannel string
-	for idx := range storageClients.Items {
-		// empty if annotation doesn't exist or else gets desired channel
-		channel := storageClients.
-			Items[idx].
-			GetAnnotations()[utils.DesiredSubscriptionChannelAnnotationKey]
-		// skip clients with no/empty desired channel annotation
-		if channel != "" {
-			// check if we already established a desired channel
-			if desiredChannel == "" {
-				desiredChannel = channel
-			}
-			// check for agreement between clients
-			if channel != desiredChannel {
-				desiredChannel = ""
-				// two clients didn't agree for a same channel and no need to continue further
-				break
+	var plan []string
+	addChanges := func(owner string, containers []corev1.Container, images map[string]string) {
+		for i := range containers {
+			container := &containers[i]
+			imageSetKey, ok := csiContainerImageSetKeys[container.Name]
+			if !ok || images[imageSetKey] == "" || images[imageSetKey] == container.Image {
+				continue
 			}
+			plan = append(plan, fmt.Sprintf("%s/%s: %s -> %s", owner, container.Name, container.Image, images[imageSetKey]))
 		}
 	}
+	for _, driverName := range []string{templates.RBDDriverName, templates.CephFsDriverName, templates.NfsDriverName} {
+		images := defaultImages
+		if driverName == templates.RBDDriverName {
+			images = rbdImages
+		}
 
-	clientSubscription, err := getSubscriptionByPackageName(c.ctx, c.Client, c.OperatorNamespace, "ocs-client-operator")
-	if err != nil {
-		return "", err
-	}
+		ctrlPlugin := &appsv1.Deployment{}
+		ctrlPlugin.Name = driverName + csiCtrlPluginSuffix
+		ctrlPlugin.Namespace = c.OperatorNamespace
+		if err := c.get(ctrlPlugin); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get csi controller plugin %q: %v", ctrlPlugin.Name, err)
+		}
+		addChanges(ctrlPlugin.Name, ctrlPlugin.Spec.Template.Spec.Containers, images)
 
-	if desiredChannel != clientSubscription.Spec.Channel {
-		// this condition happens when the provider is ahead of the client(s) but client operator upgrade is not
-		// triggered, we shouldn't error out here but go ahead and complete the current reconcile
-		c.log.Info("Skipping subs update of dependencies: client operator Subscription channel needs an update")
-		return "", nil
+		nodePlugin := &appsv1.DaemonSet{}
+		nodePlugin.Name = driverName + csiNodePluginSuffix
+		nodePlugin.Namespace = c.OperatorNamespace
+		if err := c.get(nodePlugin); client.IgnoreNotFound(err) != nil {
+			return fmt.Errorf("failed to get csi node plugin %q: %v", nodePlugin.Name, err)
+		}
+		addChanges(nodePlugin.Name, nodePlugin.Spec.Template.Spec.Containers, images)
 	}
 
-	return desiredChannel, nil
+	// the plan shrinks while the plugins roll out, it is reported once per target images
+	targetImagesHash := utils.GetMD5Hash(fmt.Sprint(defaultImages, rbdImages))
+	if len(plan) == 0 || c.reportedCSIImageUpgrade == targetImagesHash {
+		return nil
+	}
+	c.reportedCSIImageUpgrade = targetImagesHash
+	c.log.Info("csi images are about to be upgraded", "plan", plan)
+	if c.Recorder != nil {
+		c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeNormal, "CSIImagesUpgrade", "Upgrade",
+			"csi images are about to be upgraded: %s", strings.Join(plan, ", "))
+	}
+	return nil
 }
 
 func (c *OperatorConfigMapReconciler) getImageSetConfigMapName(clusterVersion string) (string, error) {
@@ -1318,6 +3699,175 @@ func (c *OperatorConfigMapReconciler) getImageSetConfigMapName(clusterVersion st
 	return configMapName, nil
 }
 
+// GetSupportedCSIImageVersions returns the platform versions, in ascending order, of the CSI image sets available in
+// namespace. Platforms newer than the last version fall back to its images.
+func GetSupportedCSIImageVersions(ctx context.Context, kubeClient client.Reader, namespace string) ([]string, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := kubeClient.List(ctx, configMaps, client.InNamespace(namespace), client.HasLabels{csiImagesConfigMapLabel}); err != nil {
+		return nil, fmt.Errorf("failed to list csi image set configmaps: %v", err)
+	}
+	var imageVersions []*version.Version
+	for idx := range configMaps.Items {
+		imageVersion, err := version.ParseGeneric(configMaps.Items[idx].GetLabels()[csiImagesConfigMapLabel])
+		if err != nil {
+			continue
+		}
+		imageVersions = append(imageVersions, imageVersion)
+	}
+	slices.SortFunc(imageVersions, func(a, b *version.Version) int {
+		if a.LessThan(b) {
+			return -1
+		} else if b.LessThan(a) {
+			return 1
+		}
+		return 0
+	})
+
+	var supportedVersions []string
+	for _, imageVersion := range imageVersions {
+		if versionString := imageVersion.String(); !slices.Contains(supportedVersions, versionString) {
+			supportedVersions = append(supportedVersions, versionString)
+		}
+	}
+	return supportedVersions, nil
+}
+
+// NewSupportedCSIImageVersionsHandler serves the versions returned by GetSupportedCSIImageVersions as a JSON list.
+func NewSupportedCSIImageVersionsHandler(kubeClient client.Reader, namespace string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		supportedVersions, err := GetSupportedCSIImageVersions(r.Context(), kubeClient, namespace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(supportedVersions); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+// NewManualReconcileHandler returns a handler which, on POST, sends an event for the operator configmap in
+// namespace to trigger. A reconcile that is already pending is reported with StatusTooManyRequests.
+func NewManualReconcileHandler(trigger chan<- event.GenericEvent, namespace string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		operatorConfigMap := &corev1.ConfigMap{}
+		operatorConfigMap.Name = operatorConfigMapName
+		operatorConfigMap.Namespace = namespace
+		select {
+		case trigger <- event.GenericEvent{Object: operatorConfigMap}:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "a manual reconcile is already pending", http.StatusTooManyRequests)
+		}
+	})
+}
+
+// ResourceHealth is the entry of a managed resource in the health summary. Ready is omitted for resources without
+// a notion of readiness.
+type ResourceHealth struct {
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Present bool   `json:"present"`
+	Ready   *bool  `json:"ready,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// GetHealthSummary reports the presence, and readiness where applicable, of the resources deployed by the operator
+// and by ceph-csi-operator on its behalf.
+func GetHealthSummary(ctx context.Context, kubeClient client.Reader, namespace string) ([]ResourceHealth, error) {
+	var summary []ResourceHealth
+	check := func(obj client.Object, readiness func() (bool, string)) error {
+		health := ResourceHealth{Kind: reflect.TypeOf(obj).Elem().Name(), Name: obj.GetName()}
+		if err := kubeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); kerrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			health.Message = "not found"
+		} else if err != nil {
+			return fmt.Errorf("failed to get %s %q: %v", health.Kind, health.Name, err)
+		} else {
+			health.Present = true
+			if readiness != nil {
+				ready, message := readiness()
+				health.Ready, health.Message = &ready, message
+			}
+		}
+		summary = append(summary, health)
+		return nil
+	}
+
+	for _, driverName := range []string{templates.RBDDriverName, templates.CephFsDriverName, templates.NfsDriverName} {
+		ctrlPlugin := &appsv1.Deployment{}
+		ctrlPlugin.Name = driverName + csiCtrlPluginSuffix
+		ctrlPlugin.Namespace = namespace
+		if err := check(ctrlPlugin, func() (bool, string) {
+			replicas := ptr.Deref(ctrlPlugin.Spec.Replicas, 1)
+			return ctrlPlugin.Status.AvailableReplicas >= replicas,
+				fmt.Sprintf("%d/%d replicas available", ctrlPlugin.Status.AvailableReplicas, replicas)
+		}); err != nil {
+			return nil, err
+		}
+
+		nodePlugin := &appsv1.DaemonSet{}
+		nodePlugin.Name = driverName + csiNodePluginSuffix
+		nodePlugin.Namespace = namespace
+		if err := check(nodePlugin, func() (bool, string) {
+			return nodePlugin.Status.NumberReady >= nodePlugin.Status.DesiredNumberScheduled,
+				fmt.Sprintf("%d/%d pods ready", nodePlugin.Status.NumberReady, nodePlugin.Status.DesiredNumberScheduled)
+		}); err != nil {
+			return nil, err
+		}
+
+		csiDriver := &storagev1.CSIDriver{}
+		csiDriver.Name = driverName
+		if err := check(csiDriver, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	consoleDeployment := &appsv1.Deployment{}
+	consoleDeployment.Name = console.DeploymentName
+	consoleDeployment.Namespace = namespace
+	if err := check(consoleDeployment, func() (bool, string) {
+		replicas := ptr.Deref(consoleDeployment.Spec.Replicas, 1)
+		return consoleDeployment.Status.AvailableReplicas >= replicas,
+			fmt.Sprintf("%d/%d replicas available", consoleDeployment.Status.AvailableReplicas, replicas)
+	}); err != nil {
+		return nil, err
+	}
+
+	scc := &secv1.SecurityContextConstraints{}
+	scc.Name = templates.SCCName
+	webhook := &admrv1.ValidatingWebhookConfiguration{}
+	webhook.Name = templates.SubscriptionWebhookName
+	consolePlugin := &consolev1.ConsolePlugin{}
+	consolePlugin.Name = console.PluginName
+	for _, obj := range []client.Object{scc, webhook, consolePlugin} {
+		if err := check(obj, nil); err != nil {
+			return nil, err
+		}
+	}
+	return summary, nil
+}
+
+// NewHealthSummaryHandler serves the summary returned by GetHealthSummary as JSON.
+func NewHealthSummaryHandler(kubeClient client.Reader, namespace string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		summary, err := GetHealthSummary(r.Context(), kubeClient, namespace)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(summary); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
 func (c *OperatorConfigMapReconciler) deleteDelegatedCSI() error {
 	// NOTE: csi operator config and driver CRs are garbage collected via ownerref, so we need to remove only SCC
 	scc := &secv1.SecurityContextConstraints{}
@@ -1325,7 +3875,8 @@ func (c *OperatorConfigMapReconciler) deleteDelegatedCSI() error {
 	if err := c.delete(scc); err != nil {
 		return err
 	}
-	return nil
+	// the pdbs would block draining nodes until garbage collected
+	return c.reconcileCSIProvisionerPDBs(nil)
 }
 
 func addSubscriptionPackageIndexer(ctx context.Context, mgr ctrl.Manager) error {
@@ -1495,6 +4046,448 @@ func buildContainerExtraArgs(tlsProfile *ocstlsv1.TLSProfile) (map[string][]stri
 	return extraArgs, nil
 }
 
+// addContainerExtraArgs appends the args of each container in args to extraArgs, allocating extraArgs if needed.
+// The arg slices are copied so that the maps never share backing arrays.
+func addContainerExtraArgs(extraArgs, args map[string][]string) map[string][]string {
+	if len(args) == 0 {
+		return extraArgs
+	}
+	if extraArgs == nil {
+		extraArgs = map[string][]string{}
+	}
+	for name, containerArgs := range args {
+		extraArgs[name] = append(slices.Clone(extraArgs[name]), containerArgs...)
+	}
+	return extraArgs
+}
+
+// setContainerExtraArgs adds the args of each container in args to extraArgs like addContainerExtraArgs, but
+// replaces the args of extraArgs with the same flag name instead of appending duplicates.
+func setContainerExtraArgs(extraArgs, args map[string][]string) map[string][]string {
+	flagName := func(arg string) string {
+		name, _, _ := strings.Cut(arg, "=")
+		return name
+	}
+	for name, containerArgs := range args {
+		if extraArgs[name] == nil {
+			continue
+		}
+		extraArgs[name] = slices.DeleteFunc(slices.Clone(extraArgs[name]), func(arg string) bool {
+			return slices.ContainsFunc(containerArgs, func(containerArg string) bool {
+				return flagName(containerArg) == flagName(arg)
+			})
+		})
+	}
+	return addContainerExtraArgs(extraArgs, args)
+}
+
+// getCSISocketPathExtraArgs returns the container args pointing the controller plugin and the sidecars dialing
+// the CSI socket to the configured one. The csi-addons sidecar dials the separate csi-addons socket, which stays
+// in the same directory, and the node plugin is left untouched as its socket path is registered with the kubelet.
+func (c *OperatorConfigMapReconciler) getCSISocketPathExtraArgs() (map[string][]string, error) {
+	socketPath := strings.TrimSpace(c.operatorConfigMap.Data[csiSocketPathKey])
+	if socketPath == "" {
+		return nil, nil
+	}
+	socketPath = strings.TrimPrefix(socketPath, "unix://")
+	if !path.IsAbs(socketPath) || path.Clean(socketPath) != socketPath {
+		return nil, fmt.Errorf("invalid value %q under %s key: must be a clean absolute path", socketPath, csiSocketPathKey)
+	}
+	if path.Dir(socketPath) != templates.CSISocketDir {
+		return nil, fmt.Errorf("invalid value %q under %s key: socket must be placed directly under %s",
+			socketPath, csiSocketPathKey, templates.CSISocketDir)
+	}
+
+	endpointArg := fmt.Sprintf("--endpoint=unix://%s", socketPath)
+	csiAddressArg := fmt.Sprintf("--csi-address=%s", socketPath)
+	return map[string][]string{
+		templates.RBDPluginContainerName:        {endpointArg},
+		templates.CephFsPluginContainerName:     {endpointArg},
+		templates.NfsPluginContainerName:        {endpointArg},
+		templates.ProvisionerContainerName:      {csiAddressArg},
+		templates.AttacherContainerName:         {csiAddressArg},
+		templates.ResizerContainerName:          {csiAddressArg},
+		templates.SnapshotterContainerName:      {csiAddressArg},
+		templates.SnapshotMetadataContainerName: {csiAddressArg},
+	}, nil
+}
+
+// getCephFsClientType returns the cephfs client selected under cephFsMounterKey, empty if unset.
+func (c *OperatorConfigMapReconciler) getCephFsClientType() (csiopv1.CephFsClientType, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[cephFsMounterKey])
+	switch csiopv1.CephFsClientType(value) {
+	case "":
+		return "", nil
+	case csiopv1.KernelCephFsClient, csiopv1.AutoDetectCephFsClient:
+		return csiopv1.CephFsClientType(value), nil
+	case "fuse":
+		return "", fmt.Errorf("invalid value %q under %s key: ceph-csi can't force the fuse client, use %q to fall back to it",
+			value, cephFsMounterKey, csiopv1.AutoDetectCephFsClient)
+	}
+	return "", fmt.Errorf("invalid value %q under %s key: must be one of %q or %q",
+		value, cephFsMounterKey, csiopv1.KernelCephFsClient, csiopv1.AutoDetectCephFsClient)
+}
+
+// getCSINodePluginUpdateStrategy returns the node plugin daemonset update strategy set under the
+// CSI_NODE_PLUGIN_UPDATE_STRATEGY key, or nil if unset.
+func (c *OperatorConfigMapReconciler) getCSINodePluginUpdateStrategy() (*appsv1.DaemonSetUpdateStrategy, error) {
+	strategyType := appsv1.DaemonSetUpdateStrategyType(strings.TrimSpace(c.getOperatorConfigValue(csiNodePluginUpdateStrategyKey, "")))
+	switch strategyType {
+	case "":
+		return nil, nil
+	case appsv1.RollingUpdateDaemonSetStrategyType, appsv1.OnDeleteDaemonSetStrategyType:
+		return &appsv1.DaemonSetUpdateStrategy{Type: strategyType}, nil
+	}
+	return nil, fmt.Errorf("invalid value %q under %s key: must be %s or %s", strategyType, csiNodePluginUpdateStrategyKey,
+		appsv1.RollingUpdateDaemonSetStrategyType, appsv1.OnDeleteDaemonSetStrategyType)
+}
+
+// getCSIArchitectureAffinity returns the node affinity restricting the CSI pods to the architectures listed under
+// the CSI_SUPPORTED_ARCHITECTURES key, or nil to schedule them on all nodes if the key is unset.
+func (c *OperatorConfigMapReconciler) getCSIArchitectureAffinity() (*corev1.Affinity, error) {
+	var architectures []string
+	for arch := range strings.SplitSeq(c.getOperatorConfigValue(csiSupportedArchitecturesKey, ""), ",") {
+		arch = strings.TrimSpace(arch)
+		if arch == "" || slices.Contains(architectures, arch) {
+			continue
+		}
+		if errs := validation.IsValidLabelValue(arch); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid architecture %q under %s key: %s", arch, csiSupportedArchitecturesKey, strings.Join(errs, ", "))
+		}
+		architectures = append(architectures, arch)
+	}
+	if len(architectures) == 0 {
+		return nil, nil
+	}
+	return &corev1.Affinity{
+		NodeAffinity: &corev1.NodeAffinity{
+			RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+				NodeSelectorTerms: []corev1.NodeSelectorTerm{{
+					MatchExpressions: []corev1.NodeSelectorRequirement{{
+						Key:      corev1.LabelArchStable,
+						Operator: corev1.NodeSelectorOpIn,
+						Values:   architectures,
+					}},
+				}},
+			},
+		},
+	}, nil
+}
+
+func (c *OperatorConfigMapReconciler) shouldSuspendCSIProvisioners() bool {
+	provisionersSuspended, _ := utils.ParseBool(c.getOperatorConfigValue(csiProvisionersSuspendedKey, "false"))
+	return provisionersSuspended
+}
+
+func (c *OperatorConfigMapReconciler) shouldMountClusterTrustBundle() bool {
+	mountTrustBundle, _ := utils.ParseBool(c.getOperatorConfigValue(mountClusterTrustBundleKey, "false"))
+	return mountTrustBundle
+}
+
+// reconcileTrustedCABundleConfigMap creates the configmap OpenShift injects the cluster wide trust bundle into, or
+// removes it when the trust bundle isn't mounted.
+func (c *OperatorConfigMapReconciler) reconcileTrustedCABundleConfigMap(mountTrustBundle bool) error {
+	trustedCABundle := &corev1.ConfigMap{}
+	trustedCABundle.Name = templates.TrustedCABundleConfigMapName
+	trustedCABundle.Namespace = c.OperatorNamespace
+	if !mountTrustBundle {
+		if err := c.delete(trustedCABundle); err != nil {
+			return fmt.Errorf("failed to delete trusted ca bundle configmap: %v", err)
+		}
+		return nil
+	}
+	if err := c.createOrUpdate(trustedCABundle, func() error {
+		// the data is managed by the injection
+		utils.AddLabel(trustedCABundle, templates.TrustedCABundleInjectLabelKey, "true")
+		return c.own(trustedCABundle)
+	}); err != nil {
+		return fmt.Errorf("failed to reconcile trusted ca bundle configmap: %v", err)
+	}
+	return nil
+}
+
+// getCSIKubeletDirPath returns the kubelet directory configured under the KUBELET_DIR_PATH key, if any.
+func (c *OperatorConfigMapReconciler) getCSIKubeletDirPath() (string, error) {
+	kubeletDirPath := strings.TrimSpace(c.operatorConfigMap.Data[kubeletDirPathKey])
+	if kubeletDirPath == "" {
+		return "", nil
+	}
+	if !path.IsAbs(kubeletDirPath) || path.Clean(kubeletDirPath) != kubeletDirPath || kubeletDirPath == "/" {
+		return "", fmt.Errorf("invalid value %q under %s key: must be a clean absolute path", kubeletDirPath, kubeletDirPathKey)
+	}
+	return kubeletDirPath, nil
+}
+
+// getCSIProvisionerFeatureGateExtraArgs returns the --feature-gates arg for the provisioner container built
+// from the CSI_PROVISIONER_FEATURE_GATES key.
+func (c *OperatorConfigMapReconciler) getCSIProvisionerFeatureGateExtraArgs() (map[string][]string, error) {
+	var featureGates []string
+	for featureGate := range strings.SplitSeq(c.operatorConfigMap.Data[csiProvisionerFeatureGatesKey], ",") {
+		featureGate = strings.TrimSpace(featureGate)
+		if featureGate == "" {
+			continue
+		}
+		name, value, found := strings.Cut(featureGate, "=")
+		name = strings.TrimSpace(name)
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if !found || name == "" || err != nil {
+			return nil, fmt.Errorf("invalid feature gate %q under %s key: expected \"Name=bool\"",
+				featureGate, csiProvisionerFeatureGatesKey)
+		}
+		featureGates = append(featureGates, fmt.Sprintf("%s=%t", name, enabled))
+	}
+	if len(featureGates) == 0 {
+		return nil, nil
+	}
+	return map[string][]string{
+		templates.ProvisionerContainerName: {fmt.Sprintf("--feature-gates=%s", strings.Join(featureGates, ","))},
+	}, nil
+}
+
+// getCSIVolumeAttributesClassExtraArgs returns the feature gate args enabling VolumeAttributesClass support in
+// the provisioner and resizer, which performs the ControllerModifyVolume calls, when the
+// CSI_ENABLE_VOLUME_ATTRIBUTES_CLASS key is true. The args are omitted if the cluster doesn't serve the API.
+func (c *OperatorConfigMapReconciler) getCSIVolumeAttributesClassExtraArgs() (map[string][]string, error) {
+	value := strings.TrimSpace(c.getOperatorConfigValue(csiEnableVolumeAttributesClassKey, ""))
+	if value == "" {
+		return nil, nil
+	}
+	enabled, err := utils.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse value for %q in operator configmap as a boolean: %v", csiEnableVolumeAttributesClassKey, err)
+	}
+	if !enabled {
+		return nil, nil
+	}
+	if !c.AvailableCrds[VolumeAttributesClassResourceName] {
+		c.log.Info("VolumeAttributesClass API is not served by the cluster, not enabling the feature in the csi sidecars")
+		return nil, nil
+	}
+	featureGateArg := "--feature-gates=VolumeAttributesClass=true"
+	return map[string][]string{
+		templates.ProvisionerContainerName: {featureGateArg},
+		templates.ResizerContainerName:     {featureGateArg},
+	}, nil
+}
+
+// getCSIExtraCreateMetadataExtraArgs returns the --extra-create-metadata arg for the provisioner container when
+// the CSI_EXTRA_CREATE_METADATA key is set, leaving the ceph-csi-operator default in place otherwise.
+func (c *OperatorConfigMapReconciler) getCSIExtraCreateMetadataExtraArgs() (map[string][]string, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[csiExtraCreateMetadataKey])
+	if value == "" {
+		return nil, nil
+	}
+	extraCreateMetadata, err := utils.ParseBool(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse value for %q in operator configmap as a boolean: %v", csiExtraCreateMetadataKey, err)
+	}
+	return map[string][]string{
+		templates.ProvisionerContainerName: {fmt.Sprintf("--extra-create-metadata=%t", extraCreateMetadata)},
+	}, nil
+}
+
+// getCSIAttacherTimeoutExtraArgs returns the --timeout arg for the attacher container from the
+// CSI_ATTACHER_TIMEOUT key.
+func (c *OperatorConfigMapReconciler) getCSIAttacherTimeoutExtraArgs() (map[string][]string, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[csiAttacherTimeoutKey])
+	if value == "" {
+		return nil, nil
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		return nil, fmt.Errorf("invalid value %q under %s key: must be a positive duration", value, csiAttacherTimeoutKey)
+	}
+	return map[string][]string{
+		templates.AttacherContainerName: {fmt.Sprintf("--timeout=%s", timeout)},
+	}, nil
+}
+
+// getCSIProvisionerRetryIntervalMaxExtraArgs returns the --retry-interval-max arg for the provisioner container
+// from the CSI_PROVISIONER_RETRY_INTERVAL_MAX key.
+func (c *OperatorConfigMapReconciler) getCSIProvisionerRetryIntervalMaxExtraArgs() (map[string][]string, error) {
+	value := strings.TrimSpace(c.operatorConfigMap.Data[csiProvisionerRetryIntervalMaxKey])
+	if value == "" {
+		return nil, nil
+	}
+	retryIntervalMax, err := time.ParseDuration(value)
+	if err != nil || retryIntervalMax <= 0 {
+		return nil, fmt.Errorf("invalid value %q under %s key: must be a positive duration", value, csiProvisionerRetryIntervalMaxKey)
+	}
+	return map[string][]string{
+		templates.ProvisionerContainerName: {fmt.Sprintf("--retry-interval-max=%s", retryIntervalMax)},
+	}, nil
+}
+
+// getCSIVolumeNameExtraArgs returns the --volume-name-prefix and --volume-name-uuid-length args for the
+// provisioner container from the CSI_VOLUME_NAME_PREFIX and CSI_VOLUME_NAME_UUID_LENGTH keys.
+func (c *OperatorConfigMapReconciler) getCSIVolumeNameExtraArgs() (map[string][]string, error) {
+	var args []string
+	if prefix := strings.TrimSpace(c.getOperatorConfigValue(csiVolumeNamePrefixKey, "")); prefix != "" {
+		if errs := validation.IsDNS1123Label(prefix); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value %q under %s key: %s", prefix, csiVolumeNamePrefixKey, strings.Join(errs, ", "))
+		}
+		args = append(args, fmt.Sprintf("--volume-name-prefix=%s", prefix))
+	}
+	if value := strings.TrimSpace(c.getOperatorConfigValue(csiVolumeNameUUIDLengthKey, "")); value != "" {
+		length, err := strconv.Atoi(value)
+		if err != nil || length <= 0 {
+			return nil, fmt.Errorf("invalid value %q under %s key: must be a positive integer", value, csiVolumeNameUUIDLengthKey)
+		}
+		args = append(args, fmt.Sprintf("--volume-name-uuid-length=%d", length))
+	}
+	if len(args) == 0 {
+		return nil, nil
+	}
+	return map[string][]string{templates.ProvisionerContainerName: args}, nil
+}
+
+// getRBDDefaultFsTypeExtraArgs returns the --default-fstype arg for the rbd provisioner container when the
+// RBD_DEFAULT_FSTYPE key is set, leaving the provisioner default in place otherwise.
+func (c *OperatorConfigMapReconciler) getRBDDefaultFsTypeExtraArgs() (map[string][]string, error) {
+	fsType := strings.TrimSpace(c.getOperatorConfigValue(rbdDefaultFsTypeKey, ""))
+	if fsType == "" {
+		return nil, nil
+	}
+	if !slices.Contains(supportedRBDFsTypes, fsType) {
+		return nil, fmt.Errorf("invalid value %q under %s key: must be one of %v", fsType, rbdDefaultFsTypeKey, supportedRBDFsTypes)
+	}
+	return map[string][]string{
+		templates.ProvisionerContainerName: {fmt.Sprintf("--default-fstype=%s", fsType)},
+	}, nil
+}
+
+// buildCSIPodAnnotations returns the pod annotations configured under the CSI_POD_ANNOTATIONS key merged with
+// the annotations required by the operator, the latter taking precedence on conflicting keys.
+func (c *OperatorConfigMapReconciler) buildCSIPodAnnotations(required map[string]string) (map[string]string, error) {
+	var annotations map[string]string
+	for line := range strings.SplitSeq(c.operatorConfigMap.Data[csiPodAnnotationsKey], "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return nil, fmt.Errorf("invalid line %q under %s key: expected \"key: value\"", line, csiPodAnnotationsKey)
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[key] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if errs := apivalidation.ValidateAnnotations(annotations, field.NewPath(csiPodAnnotationsKey)); len(errs) > 0 {
+		return nil, fmt.Errorf("invalid annotations under %s key: %v", csiPodAnnotationsKey, errs.ToAggregate())
+	}
+
+	if len(required) > 0 && annotations == nil {
+		annotations = map[string]string{}
+	}
+	maps.Copy(annotations, required)
+	return annotations, nil
+}
+
+func (c *OperatorConfigMapReconciler) shouldDisableMeshInjection() bool {
+	disableMeshInjection, _ := utils.ParseBool(c.getOperatorConfigValue(disableMeshInjectionKey, "false"))
+	return disableMeshInjection
+}
+
+// reconcileCSINamespaceLabels merges the labels configured under the CSI_NAMESPACE_LABELS key, and the mesh injection
+// opt-out when DISABLE_MESH_INJECTION is true, into the operator namespace, which hosts the CSI pods. Other labels of
+// the namespace are left untouched, so labels removed from the key aren't removed from the namespace either.
+func (c *OperatorConfigMapReconciler) reconcileCSINamespaceLabels() error {
+	var labels map[string]string
+	for line := range strings.SplitSeq(c.getOperatorConfigValue(csiNamespaceLabelsKey, ""), "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			return fmt.Errorf("invalid line %q under %s key: expected \"key: value\"", line, csiNamespaceLabelsKey)
+		}
+		if labels == nil {
+			labels = map[string]string{}
+		}
+		labels[key] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	if errs := metav1validation.ValidateLabels(labels, field.NewPath(csiNamespaceLabelsKey)); len(errs) > 0 {
+		return fmt.Errorf("invalid labels under %s key: %v", csiNamespaceLabelsKey, errs.ToAggregate())
+	}
+
+	metadata := map[string]any{}
+	if c.shouldDisableMeshInjection() {
+		// an injection label set under CSI_NAMESPACE_LABELS takes precedence
+		if _, exists := labels[istioInjectionLabelKey]; !exists {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[istioInjectionLabelKey] = meshInjectionDisabledValue
+		}
+		metadata["annotations"] = map[string]string{linkerdInjectAnnotationKey: meshInjectionDisabledValue}
+	}
+	if c.shouldManageNamespacePodSecurity() {
+		// an enforce level set under CSI_NAMESPACE_LABELS takes precedence, and is reported if it blocks the pods
+		if _, exists := labels[podSecurityEnforceLabelKey]; !exists {
+			if labels == nil {
+				labels = map[string]string{}
+			}
+			labels[podSecurityEnforceLabelKey] = podSecurityPrivilegedLevel
+		}
+	}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+
+	// a merge patch of the labels alone neither reads nor overwrites the rest of the namespace
+	patch, err := json.Marshal(map[string]any{"metadata": metadata})
+	if err != nil {
+		return err
+	}
+	namespace := &corev1.Namespace{}
+	namespace.Name = c.OperatorNamespace
+	if err := c.Patch(c.ctx, namespace, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("failed to label namespace %q: %v", namespace.Name, err)
+	}
+	return nil
+}
+
+func (c *OperatorConfigMapReconciler) shouldManageNamespacePodSecurity() bool {
+	manageNamespacePodSecurity, _ := utils.ParseBool(c.getOperatorConfigValue(manageNamespacePodSecurityKey, "false"))
+	return manageNamespacePodSecurity
+}
+
+// reportCSINamespacePodSecurity reports in the CSIPodSecurityAdmitted condition, and a warning event when it changes, whether the
+// pod security admission level enforced on the CSI namespace blocks the privileged CSI pods. A namespace without
+// the enforce label is subject to the cluster default and isn't reported.
+func (c *OperatorConfigMapReconciler) reportCSINamespacePodSecurity() error {
+	namespace := &corev1.Namespace{}
+	namespace.Name = c.OperatorNamespace
+	if err := c.get(namespace); kerrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to get namespace %q: %v", namespace.Name, err)
+	}
+
+	level, exists := namespace.Labels[podSecurityEnforceLabelKey]
+	if !exists || level == podSecurityPrivilegedLevel {
+		c.setCondition(conditionTypeCSIPodSecurityAdmitted, metav1.ConditionTrue, "PrivilegedPodsAdmitted", "")
+		return nil
+	}
+	message := fmt.Sprintf("namespace %q enforces the %q pod security level, blocking the privileged CSI pods; "+
+		"label it with %s=%s or set %s to true", namespace.Name, level, podSecurityEnforceLabelKey,
+		podSecurityPrivilegedLevel, manageNamespacePodSecurityKey)
+	if !c.setCondition(conditionTypeCSIPodSecurityAdmitted, metav1.ConditionFalse, "PrivilegedPodsBlocked", message) {
+		return nil
+	}
+	c.log.Info("the pod security level of the namespace blocks the CSI pods", "namespace", namespace.Name, "level", level)
+	if c.Recorder != nil {
+		c.Recorder.Eventf(c.operatorConfigMap, nil, corev1.EventTypeWarning, "CSIPodSecurityBlocked", "Deploy", "%s", message)
+	}
+	return nil
+}
+
 func containerTLSArgs(tlsProfile *ocstlsv1.TLSProfile, domain string) ([]string, error) {
 	goTLS, err := utils.BuildServerTLSOpts(tlsProfile, domain, "")
 	if err != nil {
@@ -3,8 +3,16 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/red-hat-storage/ocs-client-operator/api/v1alpha1"
 	"github.com/red-hat-storage/ocs-client-operator/pkg/console"
@@ -12,20 +20,44 @@ import (
 	"github.com/red-hat-storage/ocs-client-operator/pkg/utils"
 
 	csiopv1 "github.com/ceph/ceph-csi-operator/api/v1"
+	"github.com/go-logr/logr/funcr"
+	snapapi "github.com/kubernetes-csi/external-snapshotter/client/v8/apis/volumesnapshot/v1"
 	configv1 "github.com/openshift/api/config/v1"
+	consolev1 "github.com/openshift/api/console/v1"
 	secv1 "github.com/openshift/api/security/v1"
+	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	dto "github.com/prometheus/client_model/go"
 	ocstlsv1 "github.com/red-hat-storage/ocs-tls-profiles/api/v1"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	admrv1 "k8s.io/api/admissionregistration/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/events"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	ctrllog "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 const (
@@ -68,6 +100,15 @@ func newFakeScheme(t *testing.T) *runtime.Scheme {
 	err = v1alpha1.AddToScheme(scheme)
 	assert.Nil(t, err, "failed to add v1alpha1 scheme")
 
+	err = monitoringv1.AddToScheme(scheme)
+	assert.Nil(t, err, "failed to add monitoring scheme")
+
+	err = opv1a1.AddToScheme(scheme)
+	assert.Nil(t, err, "failed to add OLM scheme")
+
+	err = csiopv1.AddToScheme(scheme)
+	assert.Nil(t, err, "failed to add ceph csi operator scheme")
+
 	return scheme
 }
 
@@ -290,7 +331,8 @@ func TestTopologyLabelsFromConfigMap(t *testing.T) {
 				},
 			}
 
-			result := r.getTopologyLabels(&v1alpha1.StorageClientList{Items: tt.storageClients})
+			result, err := r.getTopologyLabels(&v1alpha1.StorageClientList{Items: tt.storageClients})
+			assert.NoError(t, err)
 
 			assert.Equal(t, len(tt.expectedLabels), len(result))
 			for _, label := range tt.expectedLabels {
@@ -301,6 +343,23 @@ func TestTopologyLabelsFromConfigMap(t *testing.T) {
 	}
 }
 
+func TestCSITopologyDomainLabels(t *testing.T) {
+	r := newDelegatedCSIReconciler(t)
+	r.operatorConfigMap.Data[utils.TopologyFailureDomainLabelsKey] = "zone"
+	r.operatorConfigMap.Data[utils.CSITopologyDomainLabelsKey] = "topology.kubernetes.io/zone, example.com/rack"
+
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	csiOperatorConfig := &csiopv1.OperatorConfig{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+	// the new key takes precedence and the labels are sorted to keep the spec stable
+	assert.Equal(t, &csiopv1.TopologySpec{
+		DomainLabels: []string{"example.com/rack", "topology.kubernetes.io/zone"},
+	}, csiOperatorConfig.Spec.DriverSpecDefaults.NodePlugin.Topology)
+
+	r.operatorConfigMap.Data[utils.CSITopologyDomainLabelsKey] = "topology.kubernetes.io/zone,not a label"
+	assert.Error(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+}
+
 func TestParseEndpointConfigs(t *testing.T) {
 	validCfg := s3EndpointConfig{EndpointURL: "https://noobaa-s3.example.com"}
 	validCfgBytes, err := json.Marshal(validCfg)
@@ -353,11 +412,60 @@ func TestBuildS3EndpointProxyConfigForClient(t *testing.T) {
 	tests := []struct {
 		name             string
 		secretData       map[string][]byte
+		operatorConfig   map[string]string
 		endpoints        map[string]s3EndpointConfig
 		expectedIncludes []string
 		expectedExcludes []string
 		expectErr        bool
 	}{
+		{
+			name: "authorization header is not forwarded by default",
+			endpoints: map[string]s3EndpointConfig{
+				"noobaaS3": {
+					EndpointURL: "https://noobaa-s3.example.com",
+				},
+			},
+			expectedIncludes: []string{"location /client-1/noobaaS3/", "proxy_ssl_verify on;"},
+			expectedExcludes: []string{"proxy_set_header Authorization", "proxy_ssl_verify off;", "add_header Cache-Control"},
+		},
+		{
+			name:           "response caching is turned off when disabled",
+			operatorConfig: map[string]string{consoleProxyDisableCacheKey: "true"},
+			endpoints: map[string]s3EndpointConfig{
+				"noobaaS3": {
+					EndpointURL: "https://noobaa-s3.example.com",
+				},
+			},
+			expectedIncludes: []string{
+				"location /client-1/noobaaS3/",
+				"proxy_hide_header Cache-Control;",
+				"add_header Cache-Control 'no-store, no-cache, must-revalidate, proxy-revalidate, max-age=0' always;",
+			},
+		},
+		{
+			name:           "tls verification is skipped when enabled",
+			operatorConfig: map[string]string{consoleProxyInsecureSkipVerifyKey: "true"},
+			endpoints: map[string]s3EndpointConfig{
+				"noobaaS3": {
+					EndpointURL: "https://noobaa-s3.example.com",
+				},
+			},
+			expectedIncludes: []string{"location /client-1/noobaaS3/", "proxy_ssl_verify off;"},
+			expectedExcludes: []string{"proxy_ssl_verify on;"},
+		},
+		{
+			name:           "authorization header is forwarded when enabled",
+			operatorConfig: map[string]string{consoleProxyForwardAuthKey: "true"},
+			endpoints: map[string]s3EndpointConfig{
+				"noobaaS3": {
+					EndpointURL: "https://noobaa-s3.example.com",
+				},
+			},
+			expectedIncludes: []string{
+				"location /client-1/noobaaS3/",
+				"proxy_set_header Authorization $http_authorization;",
+			},
+		},
 		{
 			name: "builds config for valid https endpoints only",
 			secretData: map[string][]byte{
@@ -412,6 +520,9 @@ func TestBuildS3EndpointProxyConfigForClient(t *testing.T) {
 			r.Client = newFakeClientBuilder(r.Scheme).
 				WithRuntimeObjects(secret).
 				Build()
+			if tt.operatorConfig != nil {
+				r.operatorConfigMap = &corev1.ConfigMap{Data: tt.operatorConfig}
+			}
 
 			content, buildErr := r.buildS3EndpointProxyConfigForClient("client-1", tt.endpoints)
 			if tt.expectErr {
@@ -429,6 +540,33 @@ func TestBuildS3EndpointProxyConfigForClient(t *testing.T) {
 	}
 }
 
+func TestReportConsoleProxyTLSVerify(t *testing.T) {
+	r := newSMSReconciler(t)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+	r.operatorConfigMap.Data = map[string]string{consoleProxyInsecureSkipVerifyKey: "true"}
+
+	// computing the proxy configs has no side effects
+	assert.NoError(t, r.computeDesiredProxyConfigByKey(map[string]string{}))
+	assert.Nil(t, r.getCondition(conditionTypeConsoleProxyTLSVerified))
+	assert.Empty(t, recorder.Events)
+
+	r.operatorConfigMap.Data = map[string]string{}
+	r.reportConsoleProxyTLSVerify()
+	assert.Equal(t, metav1.ConditionTrue, r.getCondition(conditionTypeConsoleProxyTLSVerified).Status)
+	assert.Empty(t, recorder.Events)
+
+	r.operatorConfigMap.Data[consoleProxyInsecureSkipVerifyKey] = "true"
+	r.reportConsoleProxyTLSVerify()
+	assert.Equal(t, metav1.ConditionFalse, r.getCondition(conditionTypeConsoleProxyTLSVerified).Status)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning ConsoleProxyInsecureSkipVerify")
+
+	// the warning is not repeated while the condition is unchanged
+	r.reportConsoleProxyTLSVerify()
+	assert.Empty(t, recorder.Events)
+}
+
 func TestBuildDesiredNginxDataWithProxies(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -475,14 +613,17 @@ func TestBuildDesiredNginxDataWithProxies(t *testing.T) {
 				WithRuntimeObjects(labeledCM).
 				Build()
 
+			rootConf, err := console.GetNginxRootConf(r.ConsolePort)
+			assert.NoError(t, err)
+
 			out, err := r.buildDesiredNginxDataWithProxies()
 			if tt.expectErr {
 				assert.Error(t, err)
 				assert.Contains(t, err.Error(), "parse endpoints ConfigMap")
-				assert.Equal(t, console.GetNginxRootConf(), out["nginx.conf"])
+				assert.Equal(t, rootConf, out[nginxRootConfKey])
 			} else {
 				assert.NoError(t, err)
-				assert.Equal(t, map[string]string{"nginx.conf": console.GetNginxRootConf()}, out)
+				assert.Equal(t, map[string]string{nginxRootConfKey: rootConf}, out)
 			}
 		})
 	}
@@ -795,3 +936,3179 @@ func TestCsiContainerTLSExtraArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcileSubscriptionValidatingWebhookOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations string
+		expected   []admrv1.OperationType
+		expectErr  bool
+	}{
+		{
+			name:     "defaults to template operations when unset",
+			expected: templates.SubscriptionValidatingWebhook.Rules[0].Operations,
+		},
+		{
+			name:       "applies configured operations",
+			operations: "create, UPDATE,update",
+			expected:   []admrv1.OperationType{admrv1.Create, admrv1.Update},
+		},
+		{
+			name:       "rejects unsupported operations",
+			operations: "CREATE,PATCH",
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSMSReconciler(t)
+			r.operatorConfigMap.Data = map[string]string{subscriptionWebhookOperationsKey: tt.operations}
+
+			err := r.reconcileSubscriptionValidatingWebhook()
+			whConfig := &admrv1.ValidatingWebhookConfiguration{}
+			getErr := r.Get(r.ctx, types.NamespacedName{Name: templates.SubscriptionWebhookName}, whConfig)
+			if tt.expectErr {
+				assert.Error(t, err)
+				assert.True(t, kerrors.IsNotFound(getErr), "webhook should not be created with invalid operations")
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, getErr)
+			assert.Len(t, whConfig.Webhooks, 1)
+			for _, rule := range whConfig.Webhooks[0].Rules {
+				assert.Equal(t, tt.expected, rule.Operations)
+			}
+		})
+	}
+}
+
+func TestReconcileSubscriptionValidatingWebhookEndpointsReadiness(t *testing.T) {
+	newEndpointSlice := func(ready bool) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      templates.WebhookServiceName + "-abcde",
+				Namespace: testNamespace,
+				Labels:    map[string]string{discoveryv1.LabelServiceName: templates.WebhookServiceName},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses:  []string{"10.0.0.1"},
+				Conditions: discoveryv1.EndpointConditions{Ready: ptr.To(ready)},
+			}},
+		}
+	}
+
+	// webhook whose CA bundle was already injected by openshift
+	injectedWebhook := &admrv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.SubscriptionWebhookName},
+		Webhooks: []admrv1.ValidatingWebhook{{
+			Name:         templates.SubscriptionWebhookName,
+			ClientConfig: admrv1.WebhookClientConfig{CABundle: []byte("injected-ca")},
+		}},
+	}
+
+	tests := []struct {
+		name                  string
+		objs                  []client.Object
+		expectedFailurePolicy admrv1.FailurePolicyType
+		expectWarning         bool
+		expectCAPending       bool
+	}{
+		{
+			name:                  "no endpoint slices",
+			objs:                  []client.Object{injectedWebhook},
+			expectedFailurePolicy: admrv1.Ignore,
+			expectWarning:         true,
+		},
+		{
+			name:                  "no ready endpoints",
+			objs:                  []client.Object{injectedWebhook, newEndpointSlice(false)},
+			expectedFailurePolicy: admrv1.Ignore,
+			expectWarning:         true,
+		},
+		{
+			name:                  "ready endpoints",
+			objs:                  []client.Object{injectedWebhook, newEndpointSlice(true)},
+			expectedFailurePolicy: admrv1.Fail,
+		},
+		{
+			name:                  "ready endpoints before the ca bundle is injected",
+			objs:                  []client.Object{newEndpointSlice(true)},
+			expectedFailurePolicy: admrv1.Ignore,
+			expectCAPending:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSMSReconciler(t, tt.objs...)
+			recorder := events.NewFakeRecorder(10)
+			r.Recorder = recorder
+
+			assert.NoError(t, r.reconcileSubscriptionValidatingWebhook())
+			whConfig := &admrv1.ValidatingWebhookConfiguration{}
+			assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.SubscriptionWebhookName}, whConfig))
+			assert.Equal(t, tt.expectedFailurePolicy, ptr.Deref(whConfig.Webhooks[0].FailurePolicy, ""))
+			assert.Equal(t, tt.expectCAPending, r.webhookCAPending)
+
+			select {
+			case event := <-recorder.Events:
+				assert.True(t, tt.expectWarning, "unexpected event %q", event)
+				assert.Contains(t, event, "Warning WebhookEndpointsNotReady")
+			default:
+				assert.False(t, tt.expectWarning, "expected a warning event")
+			}
+
+			assert.NoError(t, r.reconcileSubscriptionValidatingWebhook())
+			select {
+			case event := <-recorder.Events:
+				t.Errorf("unexpected event %q for an unchanged condition", event)
+			default:
+			}
+		})
+	}
+}
+
+func TestReconcilePrometheusRuleMirrors(t *testing.T) {
+	r := newSMSReconciler(t)
+	// the manager only caches the operator namespace, while the api server rejects writes to missing namespaces
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(
+			r.operatorConfigMap,
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-a"}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-b"}},
+		).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				if _, ok := obj.(*corev1.Namespace); ok && key.Name != testNamespace {
+					return kerrors.NewNotFound(corev1.Resource("namespaces"), key.Name)
+				}
+				return c.Get(ctx, key, obj, opts...)
+			},
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				namespace := &corev1.Namespace{}
+				if err := c.Get(ctx, client.ObjectKey{Name: obj.GetNamespace()}, namespace); err != nil {
+					return err
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	ruleKey := func(namespace string) types.NamespacedName {
+		return types.NamespacedName{Name: "prometheus-pvc-rules", Namespace: namespace}
+	}
+
+	pvcRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+
+	r.operatorConfigMap.Data = map[string]string{
+		prometheusRuleNamespacesKey: "monitoring-a, monitoring-b,monitoring-a,missing," + testNamespace,
+	}
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+	err = r.Get(r.ctx, ruleKey("missing"), &monitoringv1.PrometheusRule{})
+	assert.True(t, kerrors.IsNotFound(err), "missing namespaces should be skipped")
+
+	rule := &monitoringv1.PrometheusRule{}
+	assert.NoError(t, r.Get(r.ctx, ruleKey(testNamespace), rule))
+	assert.NotEmpty(t, rule.OwnerReferences, "rule in the operator namespace should be owned")
+	for _, namespace := range []string{"monitoring-a", "monitoring-b"} {
+		mirror := &monitoringv1.PrometheusRule{}
+		assert.NoError(t, r.Get(r.ctx, ruleKey(namespace), mirror))
+		assert.Equal(t, ManagedByLabelValue, mirror.Labels[ManagedByLabelKey])
+		assert.Empty(t, mirror.OwnerReferences, "mirrored rule can't be owned across namespaces")
+		assert.Equal(t, rule.Spec, mirror.Spec)
+	}
+
+	r.operatorConfigMap.Data = map[string]string{prometheusRuleNamespacesKey: "monitoring-b"}
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+
+	err = r.Get(r.ctx, ruleKey("monitoring-a"), &monitoringv1.PrometheusRule{})
+	assert.True(t, kerrors.IsNotFound(err), "mirror should be removed from namespaces dropped from the list")
+	assert.NoError(t, r.Get(r.ctx, ruleKey("monitoring-b"), &monitoringv1.PrometheusRule{}))
+	assert.NoError(t, r.Get(r.ctx, ruleKey(testNamespace), &monitoringv1.PrometheusRule{}))
+
+	// the remaining mirrors are removed on uninstall
+	assert.NoError(t, r.deletionPhase())
+	err = r.Get(r.ctx, ruleKey("monitoring-b"), &monitoringv1.PrometheusRule{})
+	assert.True(t, kerrors.IsNotFound(err), "mirror should be removed on uninstall")
+}
+
+func TestReconcileWithCancelledContext(t *testing.T) {
+	r := newFakeConfigMapReconciler(t)
+	r.UpdateAlertPollInterval = func(time.Duration) {}
+	operatorConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorConfigMapName, Namespace: testNamespace},
+		Data:       map[string]string{disableVersionChecksKey: "true"},
+	}
+
+	writes := 0
+	countWrite := func() { writes++ }
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(operatorConfigMap).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				countWrite()
+				return c.Create(ctx, obj, opts...)
+			},
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				countWrite()
+				return c.Update(ctx, obj, opts...)
+			},
+			Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+				countWrite()
+				return c.Patch(ctx, obj, patch, opts...)
+			},
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				countWrite()
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(operatorConfigMap)})
+	assert.NoError(t, err)
+	assert.Equal(t, contextCancelledRequeueAfter, result.RequeueAfter)
+	assert.Zero(t, writes, "no writes should be issued with a cancelled context")
+}
+
+func TestGetPvcPrometheusRule(t *testing.T) {
+	overrideRules := `
+apiVersion: monitoring.coreos.com/v1
+kind: PrometheusRule
+metadata:
+  name: custom-pvc-rules
+spec:
+  groups:
+  - name: custom-persistent-volume-alert.rules
+    rules:
+    - alert: CustomPersistentVolumeUsage
+      expr: vector(1)
+`
+	defaultRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		overrideName  string
+		overrideData  map[string]string
+		expectedGroup string
+		expectErr     bool
+	}{
+		{
+			name:          "embedded rules when no override is configured",
+			expectedGroup: defaultRule.Spec.Groups[0].Name,
+		},
+		{
+			name:          "embedded rules when override ConfigMap is absent",
+			overrideName:  "missing-rules",
+			expectedGroup: defaultRule.Spec.Groups[0].Name,
+		},
+		{
+			name:          "embedded rules when override is empty",
+			overrideName:  "pvc-rules-override",
+			overrideData:  map[string]string{pvcPrometheusRulesOverrideKey: "  "},
+			expectedGroup: defaultRule.Spec.Groups[0].Name,
+		},
+		{
+			name:          "override rules when present",
+			overrideName:  "pvc-rules-override",
+			overrideData:  map[string]string{pvcPrometheusRulesOverrideKey: overrideRules},
+			expectedGroup: "custom-persistent-volume-alert.rules",
+		},
+		{
+			name:         "invalid override rules",
+			overrideName: "pvc-rules-override",
+			overrideData: map[string]string{pvcPrometheusRulesOverrideKey: "metadata:\n  name: no-groups\n"},
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrideConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "pvc-rules-override", Namespace: testNamespace},
+				Data:       tt.overrideData,
+			}
+			r := newSMSReconciler(t, overrideConfigMap)
+			r.operatorConfigMap.Data = map[string]string{pvcPrometheusRulesOverrideConfigMapKey: tt.overrideName}
+
+			rule, err := r.getPvcPrometheusRule()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, defaultRule.Name, rule.Name, "override should replace the embedded rule in place")
+			assert.Equal(t, tt.expectedGroup, rule.Spec.Groups[0].Name)
+		})
+	}
+}
+
+func TestPvcPrometheusRuleByDeployedDrivers(t *testing.T) {
+	embeddedRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+	for _, group := range embeddedRule.Spec.Groups {
+		for _, rule := range group.Rules {
+			assert.Contains(t, rule.Expr.String(), pvcRulesProvisionerSelector)
+		}
+	}
+
+	tests := []struct {
+		name             string
+		deployedDrivers  []string
+		expectedSelector string
+		expectNoGroups   bool
+	}{
+		{
+			name:             "rbd only",
+			deployedDrivers:  []string{templates.RBDDriverName, templates.NfsDriverName},
+			expectedSelector: `provisioner=~"(.*rbd.csi.ceph.com)"`,
+		},
+		{
+			name:             "cephfs only",
+			deployedDrivers:  []string{templates.CephFsDriverName},
+			expectedSelector: `provisioner=~"(.*cephfs.csi.ceph.com)"`,
+		},
+		{
+			name:             "rbd and cephfs",
+			deployedDrivers:  []string{templates.RBDDriverName, templates.CephFsDriverName},
+			expectedSelector: `provisioner=~"` + pvcRulesProvisionerSelector + `"`,
+		},
+		{
+			name:            "nfs only",
+			deployedDrivers: []string{templates.NfsDriverName},
+			expectNoGroups:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSMSReconciler(t)
+			r.deployedCSIDrivers = map[string]bool{}
+			for _, driverName := range tt.deployedDrivers {
+				r.deployedCSIDrivers[driverName] = true
+			}
+
+			rule, err := r.getPvcPrometheusRule()
+			assert.NoError(t, err)
+			if tt.expectNoGroups {
+				assert.Empty(t, rule.Spec.Groups)
+				return
+			}
+			assert.Len(t, rule.Spec.Groups, len(embeddedRule.Spec.Groups))
+			for i, group := range rule.Spec.Groups {
+				assert.Equal(t, embeddedRule.Spec.Groups[i].Name, group.Name)
+				assert.Len(t, group.Rules, len(embeddedRule.Spec.Groups[i].Rules))
+				for _, rule := range group.Rules {
+					assert.Contains(t, rule.Expr.String(), tt.expectedSelector)
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileSecurityContextConstraintsRetriesOnConflict(t *testing.T) {
+	r := newFakeConfigMapReconciler(t)
+	r.ctx = context.Background()
+	scc := &secv1.SecurityContextConstraints{ObjectMeta: metav1.ObjectMeta{Name: templates.SCCName}}
+
+	updates := 0
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(scc).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updates++
+				if updates == 1 {
+					return kerrors.NewConflict(secv1.Resource("securitycontextconstraints"), obj.GetName(), nil)
+				}
+				return c.Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName}))
+	assert.Equal(t, 2, updates, "update should be retried after a conflict")
+
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(scc), scc))
+	assert.True(t, scc.AllowPrivilegedContainer)
+	assert.Contains(t, scc.Users, "system:serviceaccount:"+testNamespace+":ceph-csi-rbd-nodeplugin-sa")
+}
+
+func TestGetCSISocketPathExtraArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		socketPath string
+		expected   string
+		expectErr  bool
+	}{
+		{
+			name: "no args when unset",
+		},
+		{
+			name:       "plain absolute path",
+			socketPath: "/csi/custom.sock",
+			expected:   "/csi/custom.sock",
+		},
+		{
+			name:       "unix scheme is accepted",
+			socketPath: "unix:///csi/custom.sock",
+			expected:   "/csi/custom.sock",
+		},
+		{
+			name:       "relative path is rejected",
+			socketPath: "csi/custom.sock",
+			expectErr:  true,
+		},
+		{
+			name:       "path outside the socket dir is rejected",
+			socketPath: "/var/run/custom.sock",
+			expectErr:  true,
+		},
+		{
+			name:       "unclean path is rejected",
+			socketPath: "/csi/../csi/custom.sock",
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiSocketPathKey: tt.socketPath}}
+
+			args, err := r.getCSISocketPathExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			if tt.expected == "" {
+				assert.Nil(t, args)
+				return
+			}
+			for _, plugin := range []string{templates.RBDPluginContainerName, templates.CephFsPluginContainerName, templates.NfsPluginContainerName} {
+				assert.Equal(t, []string{"--endpoint=unix://" + tt.expected}, args[plugin])
+			}
+			for _, sidecar := range []string{templates.ProvisionerContainerName, templates.AttacherContainerName, templates.ResizerContainerName, templates.SnapshotterContainerName, templates.SnapshotMetadataContainerName} {
+				assert.Equal(t, []string{"--csi-address=" + tt.expected}, args[sidecar])
+			}
+		})
+	}
+}
+
+func TestSetContainerExtraArgs(t *testing.T) {
+	extraArgs := map[string][]string{
+		templates.SnapshotMetadataContainerName: {"--tls-min-version=VersionTLS12", "--csi-address=/csi/old.sock"},
+	}
+	extraArgs = setContainerExtraArgs(extraArgs, map[string][]string{
+		templates.SnapshotMetadataContainerName: {"--csi-address=/csi/custom.sock"},
+		templates.ProvisionerContainerName:      {"--csi-address=/csi/custom.sock"},
+	})
+	assert.Equal(t, map[string][]string{
+		templates.SnapshotMetadataContainerName: {"--tls-min-version=VersionTLS12", "--csi-address=/csi/custom.sock"},
+		templates.ProvisionerContainerName:      {"--csi-address=/csi/custom.sock"},
+	}, extraArgs)
+}
+
+func TestGetClientOperatorSubscription(t *testing.T) {
+	newSubscription := func(name, namespace, pkg string) *opv1a1.Subscription {
+		return &opv1a1.Subscription{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec:       &opv1a1.SubscriptionSpec{Package: pkg},
+		}
+	}
+	packageIndexer := func(o client.Object) []string {
+		return []string{o.(*opv1a1.Subscription).Spec.Package}
+	}
+
+	tests := []struct {
+		name          string
+		objs          []client.Object
+		expectedNs    string
+		expectMissing bool
+	}{
+		{
+			name: "subscription in the operator namespace",
+			objs: []client.Object{
+				newSubscription("client", testNamespace, clientOperatorPackageName),
+				newSubscription("other", "other-ns", "other-operator"),
+			},
+			expectedNs: testNamespace,
+		},
+		{
+			name: "subscription outside the operator namespace",
+			objs: []client.Object{
+				newSubscription("client", "openshift-operators", clientOperatorPackageName),
+			},
+			expectedNs: "openshift-operators",
+		},
+		{
+			name: "operator namespace takes precedence",
+			objs: []client.Object{
+				newSubscription("client", "openshift-operators", clientOperatorPackageName),
+				newSubscription("client", testNamespace, clientOperatorPackageName),
+			},
+			expectedNs: testNamespace,
+		},
+		{
+			name:          "no subscription",
+			objs:          []client.Object{newSubscription("other", testNamespace, "other-operator")},
+			expectMissing: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := newFakeScheme(t)
+			kubeClient := newFakeClientBuilder(scheme).
+				WithObjects(tt.objs...).
+				WithIndex(&opv1a1.Subscription{}, subPackageIndexName, packageIndexer).
+				Build()
+
+			sub, err := getClientOperatorSubscription(context.Background(), kubeClient, testNamespace)
+			if tt.expectMissing {
+				assert.True(t, kerrors.IsNotFound(err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedNs, sub.Namespace)
+		})
+	}
+}
+
+func TestUnlabelClientOperatorSubscription(t *testing.T) {
+	subscription := &opv1a1.Subscription{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "client",
+			Namespace: testNamespace,
+			Labels:    map[string]string{subscriptionLabelKey: subscriptionLabelValue, "other": "value"},
+		},
+		Spec: &opv1a1.SubscriptionSpec{Package: clientOperatorPackageName},
+	}
+	r := newSMSReconciler(t)
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(subscription).
+		WithIndex(&opv1a1.Subscription{}, subPackageIndexName, func(o client.Object) []string {
+			return []string{o.(*opv1a1.Subscription).Spec.Package}
+		}).
+		Build()
+	getLabels := func() map[string]string {
+		actual := &opv1a1.Subscription{}
+		assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(subscription), actual))
+		return actual.Labels
+	}
+
+	assert.NoError(t, r.unlabelClientOperatorSubscription())
+	assert.Equal(t, map[string]string{"other": "value"}, getLabels())
+
+	// a managed-by label not set for the webhook is left alone
+	actual := &opv1a1.Subscription{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(subscription), actual))
+	actual.Labels[subscriptionLabelKey] = "someone-else"
+	assert.NoError(t, r.Update(r.ctx, actual))
+	assert.NoError(t, r.unlabelClientOperatorSubscription())
+	assert.Equal(t, "someone-else", getLabels()[subscriptionLabelKey])
+
+	// a missing subscription is not an error
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithIndex(&opv1a1.Subscription{}, subPackageIndexName, func(o client.Object) []string {
+			return []string{o.(*opv1a1.Subscription).Spec.Package}
+		}).
+		Build()
+	assert.NoError(t, r.unlabelClientOperatorSubscription())
+}
+
+func TestBuildCSIPodAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations string
+		required    map[string]string
+		expected    map[string]string
+		expectErr   bool
+	}{
+		{
+			name: "no annotations when unset",
+		},
+		{
+			name:     "required annotations only",
+			required: map[string]string{cniNetworksAnnotationKey: "openshift-storage/public-net"},
+			expected: map[string]string{cniNetworksAnnotationKey: "openshift-storage/public-net"},
+		},
+		{
+			name:        "configured annotations",
+			annotations: "sidecar.istio.io/inject: \"false\"\n\nexample.com/team: storage\n",
+			expected: map[string]string{
+				"sidecar.istio.io/inject": "false",
+				"example.com/team":        "storage",
+			},
+		},
+		{
+			name:        "required annotations take precedence",
+			annotations: "sidecar.istio.io/inject: \"false\"\nk8s.v1.cni.cncf.io/networks: other-net",
+			required:    map[string]string{cniNetworksAnnotationKey: "openshift-storage/public-net"},
+			expected: map[string]string{
+				"sidecar.istio.io/inject": "false",
+				cniNetworksAnnotationKey:  "openshift-storage/public-net",
+			},
+		},
+		{
+			name:        "line without separator is rejected",
+			annotations: "sidecar.istio.io/inject",
+			expectErr:   true,
+		},
+		{
+			name:        "invalid annotation key is rejected",
+			annotations: "not a valid key: value",
+			expectErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiPodAnnotationsKey: tt.annotations}}
+
+			annotations, err := r.buildCSIPodAnnotations(tt.required)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, annotations)
+		})
+	}
+}
+
+func TestGetCSIProvisionerFeatureGateExtraArgs(t *testing.T) {
+	tests := []struct {
+		name         string
+		featureGates string
+		expected     map[string][]string
+		expectErr    bool
+	}{
+		{
+			name: "no args when unset",
+		},
+		{
+			name:         "single feature gate",
+			featureGates: "HonorPVReclaimPolicy=true",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--feature-gates=HonorPVReclaimPolicy=true"},
+			},
+		},
+		{
+			name:         "multiple feature gates are normalized",
+			featureGates: " HonorPVReclaimPolicy = True ,, Topology=0,",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--feature-gates=HonorPVReclaimPolicy=true,Topology=false"},
+			},
+		},
+		{
+			name:         "missing value is rejected",
+			featureGates: "HonorPVReclaimPolicy",
+			expectErr:    true,
+		},
+		{
+			name:         "non boolean value is rejected",
+			featureGates: "HonorPVReclaimPolicy=yes",
+			expectErr:    true,
+		},
+		{
+			name:         "missing name is rejected",
+			featureGates: "=true",
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiProvisionerFeatureGatesKey: tt.featureGates}}
+
+			args, err := r.getCSIProvisionerFeatureGateExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestReconcileConfigMapDefaultingWebhook(t *testing.T) {
+	r := newSMSReconciler(t)
+	deletes := 0
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(r.operatorConfigMap).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				if _, ok := obj.(*admrv1.MutatingWebhookConfiguration); ok {
+					deletes++
+				}
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	r.operatorConfigMap.Data = map[string]string{deployConfigMapDefaultingWebhookKey: "true"}
+
+	assert.NoError(t, r.reconcileConfigMapDefaultingWebhook())
+
+	whConfig := &admrv1.MutatingWebhookConfiguration{}
+	whConfig.Name = templates.ConfigMapDefaultingWebhookName
+	assert.NoError(t, r.get(whConfig))
+	assert.Len(t, whConfig.Webhooks, 1)
+	assert.Equal(t, testNamespace, whConfig.Webhooks[0].ClientConfig.Service.Namespace)
+	assert.Equal(t, templates.ConfigMapDefaultingWebhookPath, *whConfig.Webhooks[0].ClientConfig.Service.Path)
+	assert.Equal(t, `object.metadata.name == "ocs-client-operator-config"`, whConfig.Webhooks[0].MatchConditions[0].Expression)
+
+	svc := &corev1.Service{}
+	svc.Name = templates.WebhookServiceName
+	svc.Namespace = testNamespace
+	assert.NoError(t, r.get(svc))
+
+	r.operatorConfigMap.Data[deployConfigMapDefaultingWebhookKey] = "false"
+	assert.NoError(t, r.reconcileConfigMapDefaultingWebhook())
+	assert.True(t, kerrors.IsNotFound(r.get(whConfig)))
+	// the webhook is deleted once, the following reconciles find it gone
+	assert.NoError(t, r.reconcileConfigMapDefaultingWebhook())
+	assert.NoError(t, r.deleteConfigMapDefaultingWebhook())
+	assert.Equal(t, 1, deletes)
+
+	r.operatorConfigMap.Data[deployConfigMapDefaultingWebhookKey] = "maybe"
+	assert.Error(t, r.reconcileConfigMapDefaultingWebhook())
+}
+
+func TestCreateOrUpdateLogsResourceDiff(t *testing.T) {
+	for _, logDiffs := range []bool{true, false} {
+		t.Run(fmt.Sprintf("%s=%t", logResourceDiffsKey, logDiffs), func(t *testing.T) {
+			existing := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: testNamespace},
+				Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 443}}},
+			}
+			r := newSMSReconciler(t, existing)
+			r.operatorConfigMap.Data = map[string]string{logResourceDiffsKey: strconv.FormatBool(logDiffs)}
+
+			var logged []string
+			r.log = funcr.New(func(prefix, args string) {
+				logged = append(logged, args)
+			}, funcr.Options{})
+
+			svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: testNamespace}}
+			setPort := func(port int32) error {
+				return r.createOrUpdate(svc, func() error {
+					svc.Spec.Ports = []corev1.ServicePort{{Name: "https", Port: port}}
+					return nil
+				})
+			}
+
+			// unchanged resources don't log a diff
+			assert.NoError(t, setPort(443))
+			assert.NoError(t, setPort(8443))
+
+			var diffs []string
+			for _, line := range logged {
+				if strings.Contains(line, `"resource diff"`) {
+					diffs = append(diffs, line)
+				}
+			}
+			if !logDiffs {
+				assert.Empty(t, diffs)
+				return
+			}
+			assert.Len(t, diffs, 1)
+			assert.Contains(t, diffs[0], "443")
+			assert.Contains(t, diffs[0], "8443")
+		})
+	}
+}
+
+func TestCreateOrUpdateCountsDriftCorrections(t *testing.T) {
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: testNamespace},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 443}}},
+	}
+	r := newSMSReconciler(t, existing)
+
+	corrections := func() float64 {
+		m := &dto.Metric{}
+		assert.NoError(t, ResourceDriftCorrections.WithLabelValues("Service").Write(m))
+		return m.GetCounter().GetValue()
+	}
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: testNamespace}}
+	setPort := func(port int32) error {
+		return r.createOrUpdate(svc, func() error {
+			svc.Spec.Ports = []corev1.ServicePort{{Name: "https", Port: port}}
+			return nil
+		})
+	}
+
+	before := corrections()
+	// a no-op doesn't count as a correction
+	assert.NoError(t, setPort(443))
+	assert.Equal(t, before, corrections())
+
+	assert.NoError(t, setPort(8443))
+	assert.Equal(t, before+1, corrections())
+}
+
+func TestDebouncedEnqueueHandlerCoalescesBursts(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: operatorConfigMapName, Namespace: testNamespace}}
+	delay := 50 * time.Millisecond
+	h := newDebouncedEnqueueHandler(req, delay)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	ctx := context.Background()
+	obj := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm", Namespace: testNamespace}}
+	for range 100 {
+		h.Create(ctx, event.CreateEvent{Object: obj}, q)
+		h.Update(ctx, event.UpdateEvent{ObjectOld: obj, ObjectNew: obj}, q)
+	}
+	// nothing is enqueued before the debounce window elapses
+	assert.Equal(t, 0, q.Len())
+
+	assert.Eventually(t, func() bool { return q.Len() > 0 }, time.Second, 10*time.Millisecond)
+	// give any stray delayed entries the chance to show up
+	time.Sleep(2 * delay)
+	assert.Equal(t, 1, q.Len())
+
+	item, _ := q.Get()
+	assert.Equal(t, req, item)
+	q.Done(item)
+	assert.Equal(t, 0, q.Len())
+}
+
+func TestDebouncedEnqueueHandlerWaitsForConfigMapEditsToSettle(t *testing.T) {
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: operatorConfigMapName, Namespace: testNamespace}}
+	delay := 100 * time.Millisecond
+	h := newDebouncedEnqueueHandler(req, delay)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+
+	ctx := context.Background()
+	oldCM := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: operatorConfigMapName, Namespace: testNamespace}}
+	firstEdit := oldCM.DeepCopy()
+	firstEdit.Data = map[string]string{"first": "true"}
+	secondEdit := firstEdit.DeepCopy()
+	secondEdit.Data["second"] = "true"
+
+	h.Update(ctx, event.UpdateEvent{ObjectOld: oldCM, ObjectNew: firstEdit}, q)
+	time.Sleep(delay * 6 / 10)
+	h.Update(ctx, event.UpdateEvent{ObjectOld: firstEdit, ObjectNew: secondEdit}, q)
+
+	// the second edit pushes the reconcile back past the window of the first one
+	time.Sleep(delay * 6 / 10)
+	assert.Equal(t, 0, q.Len())
+
+	assert.Eventually(t, func() bool { return q.Len() > 0 }, time.Second, 10*time.Millisecond)
+	time.Sleep(2 * delay)
+	assert.Equal(t, 1, q.Len())
+}
+
+func TestOwnWithRetainOnUninstall(t *testing.T) {
+	r := newSMSReconciler(t)
+	svc := &corev1.Service{}
+	svc.Name = templates.WebhookServiceName
+	svc.Namespace = testNamespace
+
+	r.operatorConfigMap.Data = map[string]string{retainOnUninstallKey: "true"}
+	assert.NoError(t, r.reconcileWebhookService())
+	assert.NoError(t, r.get(svc))
+	assert.Nil(t, metav1.GetControllerOf(svc))
+	assert.Equal(t, ManagedByLabelValue, svc.Labels[OwnedByLabelKey])
+	assert.NotContains(t, svc.Labels, ManagedByLabelKey)
+	assert.True(t, isOwnedByOperatorConfigMap(svc))
+
+	// switching the mode off adds the controller reference
+	r.operatorConfigMap.Data[retainOnUninstallKey] = "false"
+	assert.NoError(t, r.reconcileWebhookService())
+	assert.NoError(t, r.get(svc))
+	assert.NotNil(t, metav1.GetControllerOf(svc))
+	assert.Equal(t, r.operatorConfigMap.Name, metav1.GetControllerOf(svc).Name)
+
+	// and switching it back on removes it again
+	r.operatorConfigMap.Data[retainOnUninstallKey] = "true"
+	assert.NoError(t, r.reconcileWebhookService())
+	assert.NoError(t, r.get(svc))
+	assert.Nil(t, metav1.GetControllerOf(svc))
+	assert.Empty(t, svc.OwnerReferences)
+}
+
+func TestReportCSIDriverReadiness(t *testing.T) {
+	driver := &csiopv1.Driver{ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName, Namespace: testNamespace}}
+	ctrlPlugin := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiCtrlPluginSuffix, Namespace: testNamespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+	}
+	nodePlugin := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiNodePluginSuffix, Namespace: testNamespace},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 3},
+	}
+	r := newSMSReconciler(t, driver, ctrlPlugin, nodePlugin)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	setReadyReplicas := func(replicas int32) {
+		ctrlPlugin.Status.ReadyReplicas = replicas
+		assert.NoError(t, r.Client.Status().Update(r.ctx, ctrlPlugin))
+	}
+	readyEvents := func() int {
+		count := 0
+		for {
+			select {
+			case event := <-recorder.Events:
+				assert.Contains(t, event, "Normal DriverReady")
+				count++
+			default:
+				return count
+			}
+		}
+	}
+
+	// controller plugin isn't ready yet
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	assert.Equal(t, 0, readyEvents())
+
+	setReadyReplicas(2)
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	assert.Equal(t, 1, readyEvents())
+
+	// steady state doesn't repeat the event
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	assert.Equal(t, 0, readyEvents())
+
+	// a new transition to ready emits a new event
+	setReadyReplicas(1)
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	setReadyReplicas(2)
+	assert.NoError(t, r.reportCSIDriverReadiness())
+	assert.Equal(t, 1, readyEvents())
+}
+
+func TestReconcilePrometheusRuleMirrorLabels(t *testing.T) {
+	r := newSMSReconciler(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-a"}}, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-b"}})
+	pvcRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+
+	r.operatorConfigMap.Data = map[string]string{
+		prometheusRuleNamespacesKey:                    "monitoring-a,monitoring-b",
+		ocsMetricsLabelsKey:                            "tenant: default",
+		prometheusRuleLabelsKeyPrefix + "monitoring-a": "tenant: team-a\nrole: alert-rules",
+	}
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+
+	getLabels := func(namespace string) map[string]string {
+		rule := &monitoringv1.PrometheusRule{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: pvcRule.Name, Namespace: namespace}, rule))
+		return rule.Labels
+	}
+
+	assert.Equal(t, map[string]string{
+		"tenant":          "team-a",
+		"role":            "alert-rules",
+		ManagedByLabelKey: ManagedByLabelValue,
+		OwnedByLabelKey:   ManagedByLabelValue,
+	}, getLabels("monitoring-a"))
+	assert.Equal(t, map[string]string{
+		"tenant":          "default",
+		ManagedByLabelKey: ManagedByLabelValue,
+		OwnedByLabelKey:   ManagedByLabelValue,
+	}, getLabels("monitoring-b"))
+	// the override only applies to the mirror namespace
+	assert.Equal(t, "default", getLabels(testNamespace)["tenant"])
+}
+
+func TestReconcileExtraManifests(t *testing.T) {
+	const configMapManifest = `
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: extra-config
+data:
+  key: value
+`
+	const roleManifest = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: Role
+metadata:
+  name: extra-role
+rules:
+- apiGroups: [""]
+  resources: ["configmaps"]
+  verbs: ["get"]
+`
+	const roleBindingManifest = `
+apiVersion: rbac.authorization.k8s.io/v1
+kind: RoleBinding
+metadata:
+  name: extra-rolebinding
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: Role
+  name: extra-role
+subjects:
+- kind: ServiceAccount
+  name: extra-sa
+`
+	manifests := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "extra-manifests", Namespace: testNamespace},
+		Data:       map[string]string{"manifests.yaml": configMapManifest + "---" + roleManifest + "---" + roleBindingManifest},
+	}
+	r := newSMSReconciler(t, manifests)
+	r.operatorConfigMap.Data = map[string]string{extraManifestsConfigMapKey: manifests.Name}
+
+	extraConfig := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "extra-config", Namespace: testNamespace}}
+	extraRole := &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: "extra-role", Namespace: testNamespace}}
+	extraRoleBinding := &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "extra-rolebinding", Namespace: testNamespace}}
+
+	assert.NoError(t, r.reconcileExtraManifests())
+	assert.NoError(t, r.get(extraConfig))
+	assert.Equal(t, map[string]string{"key": "value"}, extraConfig.Data)
+	assert.Equal(t, "true", extraConfig.Labels[extraManifestLabelKey])
+	assert.NotNil(t, metav1.GetControllerOf(extraConfig))
+	assert.NoError(t, r.get(extraRole))
+	assert.Len(t, extraRole.Rules, 1)
+	assert.NoError(t, r.get(extraRoleBinding))
+	assert.Equal(t, "extra-role", extraRoleBinding.RoleRef.Name)
+
+	// removing a manifest prunes its object
+	assert.NoError(t, r.get(manifests))
+	manifests.Data["manifests.yaml"] = configMapManifest
+	assert.NoError(t, r.Update(r.ctx, manifests))
+	assert.NoError(t, r.reconcileExtraManifests())
+	assert.NoError(t, r.get(extraConfig))
+	assert.True(t, kerrors.IsNotFound(r.get(extraRole)))
+	assert.True(t, kerrors.IsNotFound(r.get(extraRoleBinding)))
+
+	// kinds outside of the allowlist and bindings of cluster roles are rejected
+	for _, manifest := range []string{
+		"apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: extra-deployment\n",
+		"apiVersion: v1\nkind: Secret\nmetadata:\n  name: extra-secret\n",
+		"apiVersion: rbac.authorization.k8s.io/v1\nkind: RoleBinding\nmetadata:\n  name: extra-rolebinding\n" +
+			"roleRef:\n  apiGroup: rbac.authorization.k8s.io\n  kind: ClusterRole\n  name: admin\n",
+	} {
+		manifests.Data["rejected.yaml"] = manifest
+		assert.NoError(t, r.Update(r.ctx, manifests))
+		assert.Error(t, r.reconcileExtraManifests())
+	}
+	delete(manifests.Data, "rejected.yaml")
+
+	// objects which weren't created from the manifests aren't taken over
+	manifests.Data["operator.yaml"] = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + r.operatorConfigMap.Name + "\n"
+	assert.NoError(t, r.Update(r.ctx, manifests))
+	assert.Error(t, r.reconcileExtraManifests())
+
+	// unsetting the key prunes everything
+	delete(r.operatorConfigMap.Data, extraManifestsConfigMapKey)
+	assert.NoError(t, r.reconcileExtraManifests())
+	assert.True(t, kerrors.IsNotFound(r.get(extraConfig)))
+	assert.NoError(t, r.get(r.operatorConfigMap))
+}
+
+func TestIsReferencedConfigMap(t *testing.T) {
+	r := newSMSReconciler(t)
+	r.referencedConfigMaps = &atomic.Pointer[[]string]{}
+	assert.False(t, r.isReferencedConfigMap("extra-manifests"))
+
+	r.operatorConfigMap.Data = map[string]string{
+		extraManifestsConfigMapKey:             " extra-manifests ",
+		pvcPrometheusRulesOverrideConfigMapKey: "pvc-rules",
+	}
+	r.recordReferencedConfigMaps()
+	assert.True(t, r.isReferencedConfigMap("extra-manifests"))
+	assert.True(t, r.isReferencedConfigMap("pvc-rules"))
+	assert.False(t, r.isReferencedConfigMap("other"))
+
+	r.operatorConfigMap.Data = nil
+	r.recordReferencedConfigMaps()
+	assert.False(t, r.isReferencedConfigMap("extra-manifests"))
+}
+
+func TestPrometheusRuleEvalInterval(t *testing.T) {
+	r := newSMSReconciler(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-a"}})
+	pvcRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, pvcRule.Spec.Groups)
+
+	getGroupIntervals := func(namespace string) []*monitoringv1.Duration {
+		rule := &monitoringv1.PrometheusRule{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: pvcRule.Name, Namespace: namespace}, rule))
+		var intervals []*monitoringv1.Duration
+		for _, group := range rule.Spec.Groups {
+			intervals = append(intervals, group.Interval)
+		}
+		return intervals
+	}
+	var defaultIntervals []*monitoringv1.Duration
+	for _, group := range pvcRule.Spec.Groups {
+		defaultIntervals = append(defaultIntervals, group.Interval)
+	}
+
+	// the embedded intervals are kept when unset
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+	assert.Equal(t, defaultIntervals, getGroupIntervals(testNamespace))
+
+	r.operatorConfigMap.Data = map[string]string{
+		prometheusRuleEvalIntervalKey: "2m",
+		prometheusRuleNamespacesKey:   "monitoring-a",
+	}
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+	for _, namespace := range []string{testNamespace, "monitoring-a"} {
+		for _, interval := range getGroupIntervals(namespace) {
+			assert.Equal(t, ptr.To(monitoringv1.Duration("2m")), interval)
+		}
+	}
+	// the decoded rule isn't modified
+	for i, group := range pvcRule.Spec.Groups {
+		assert.Equal(t, defaultIntervals[i], group.Interval)
+	}
+
+	r.operatorConfigMap.Data[prometheusRuleEvalIntervalKey] = "2 minutes"
+	assert.Error(t, r.reconcilePrometheusRule(pvcRule))
+}
+
+func TestReconcilePrometheusRuleWithNilConfigMapData(t *testing.T) {
+	r := newSMSReconciler(t)
+	r.operatorConfigMap.Data = nil
+	pvcRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+
+	rule := &monitoringv1.PrometheusRule{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: pvcRule.Name, Namespace: testNamespace}, rule))
+	assert.Equal(t, map[string]string{OwnedByLabelKey: ManagedByLabelValue}, rule.Labels)
+	assert.Equal(t, "fallback", r.getOperatorConfigValue(ocsMetricsLabelsKey, "fallback"))
+
+	r.operatorConfigMap = nil
+	assert.Equal(t, "fallback", r.getOperatorConfigValue(ocsMetricsLabelsKey, "fallback"))
+}
+
+func TestGetMissingCSIRequiredSecrets(t *testing.T) {
+	present := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "kms-credentials", Namespace: testNamespace}}
+	otherNamespace := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "vault-token", Namespace: "other-ns"}}
+
+	tests := []struct {
+		name            string
+		requiredSecrets string
+		expected        []string
+	}{
+		{
+			name: "nothing required",
+		},
+		{
+			name:            "required secrets are present",
+			requiredSecrets: "kms-credentials",
+		},
+		{
+			name:            "missing secrets are reported once",
+			requiredSecrets: "kms-credentials, vault-token,,vault-token",
+			expected:        []string{"vault-token"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newSMSReconciler(t, present, otherNamespace)
+			r.operatorConfigMap.Data = map[string]string{csiRequiredSecretsKey: tt.requiredSecrets}
+
+			missing, err := r.getMissingCSIRequiredSecrets()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, missing)
+
+			available, err := r.reportCSIRequiredSecrets()
+			assert.NoError(t, err)
+			assert.Equal(t, len(tt.expected) == 0, available)
+			condition := r.getCondition(conditionTypeCSIRequiredSecretsAvailable)
+			assert.NotNil(t, condition)
+			if available {
+				assert.Equal(t, metav1.ConditionTrue, condition.Status)
+			} else {
+				assert.Equal(t, metav1.ConditionFalse, condition.Status)
+				for _, name := range tt.expected {
+					assert.Contains(t, condition.Message, name)
+				}
+			}
+		})
+	}
+}
+
+func TestRecordCSIRequiredSecrets(t *testing.T) {
+	r := newSMSReconciler(t)
+	r.csiRequiredSecrets = &atomic.Pointer[[]string]{}
+	assert.False(t, r.isCSIRequiredSecret("vault-token"))
+
+	r.operatorConfigMap.Data = map[string]string{csiRequiredSecretsKey: "kms-credentials, vault-token"}
+	r.recordCSIRequiredSecrets()
+	assert.True(t, r.isCSIRequiredSecret("kms-credentials"))
+	assert.True(t, r.isCSIRequiredSecret("vault-token"))
+	assert.False(t, r.isCSIRequiredSecret("other"))
+
+	delete(r.operatorConfigMap.Data, csiRequiredSecretsKey)
+	r.recordCSIRequiredSecrets()
+	assert.False(t, r.isCSIRequiredSecret("vault-token"))
+}
+
+func TestGetCSIExtraCreateMetadataExtraArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string][]string
+		expectErr bool
+	}{
+		{
+			name: "operator default when unset",
+		},
+		{
+			name:  "enabled",
+			value: "true",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--extra-create-metadata=true"},
+			},
+		},
+		{
+			name:  "disabled",
+			value: " False ",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--extra-create-metadata=false"},
+			},
+		},
+		{
+			name:      "invalid value is rejected",
+			value:     "sometimes",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiExtraCreateMetadataKey: tt.value}}
+
+			args, err := r.getCSIExtraCreateMetadataExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestGetConsolePorts(t *testing.T) {
+	tests := []struct {
+		name                string
+		data                map[string]string
+		expectedListenPort  int32
+		expectedServicePort int32
+		expectErr           bool
+	}{
+		{
+			name:                "defaults to the console port",
+			expectedListenPort:  9001,
+			expectedServicePort: 9001,
+		},
+		{
+			name: "differing ports",
+			data: map[string]string{
+				consoleListenPortKey:  "9002",
+				consoleServicePortKey: "9443",
+			},
+			expectedListenPort:  9002,
+			expectedServicePort: 9443,
+		},
+		{
+			name:      "invalid port is rejected",
+			data:      map[string]string{consoleServicePortKey: "70000"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.ConsolePort = 9001
+			r.operatorConfigMap = &corev1.ConfigMap{Data: tt.data}
+
+			listenPort, servicePort, err := r.getConsolePorts()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedListenPort, listenPort)
+			assert.Equal(t, tt.expectedServicePort, servicePort)
+
+			svc := console.GetService(listenPort, servicePort, testNamespace)
+			assert.Equal(t, tt.expectedListenPort, svc.Spec.Ports[0].TargetPort.IntVal)
+			assert.Equal(t, tt.expectedServicePort, svc.Spec.Ports[0].Port)
+
+			plugin := console.GetConsolePlugin(servicePort, testNamespace, false)
+			assert.Equal(t, tt.expectedServicePort, plugin.Spec.Backend.Service.Port)
+
+			rootConf, err := console.GetNginxRootConf(listenPort)
+			assert.NoError(t, err)
+			assert.Contains(t, rootConf, fmt.Sprintf("listen       %d ssl;", tt.expectedListenPort))
+		})
+	}
+}
+
+// recordingSpanExporter keeps the exported spans in memory.
+type recordingSpanExporter struct {
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordingSpanExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingSpanExporter) Shutdown(context.Context) error { return nil }
+
+func TestReconcileTracing(t *testing.T) {
+	exporter := &recordingSpanExporter{}
+	r := newSMSReconciler(t)
+	r.Tracer = sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+
+	// the reconcile of a missing configmap returns early with only the reconcile span
+	_, err := r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "missing", Namespace: testNamespace},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, exporter.spans, 1)
+	assert.Equal(t, "Reconcile", exporter.spans[0].Name())
+	exporter.spans = nil
+
+	// steps are recorded as children of the span of the reconciler context
+	ctx, reconcileSpan := r.Tracer.Start(context.Background(), "Reconcile")
+	r.ctx = ctx
+	assert.NoError(t, r.traceStep("scc", func() error { return nil }))
+	assert.Error(t, r.traceStep("csi", func() error { return fmt.Errorf("failed") }))
+	assert.Equal(t, ctx, r.ctx)
+	reconcileSpan.End()
+
+	assert.Len(t, exporter.spans, 3)
+	byName := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range exporter.spans {
+		byName[span.Name()] = span
+	}
+	for _, step := range []string{"scc", "csi"} {
+		assert.Equal(t, reconcileSpan.SpanContext().SpanID(), byName[step].Parent().SpanID())
+	}
+	assert.Equal(t, codes.Unset, byName["scc"].Status().Code)
+	assert.Equal(t, codes.Error, byName["csi"].Status().Code)
+
+	// without a tracer the steps still run
+	r.Tracer = nil
+	assert.Error(t, r.traceStep("csi", func() error { return fmt.Errorf("failed") }))
+}
+
+func TestReconcileWithCircuitBreaker(t *testing.T) {
+	r := newSMSReconciler(t)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	calls := 0
+	failing := func() error {
+		calls++
+		return fmt.Errorf("permanent failure")
+	}
+	for range circuitBreakerThreshold - 1 {
+		assert.Error(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", failing))
+	}
+	assert.False(t, r.circuitBreakerOpen)
+	assert.Empty(t, recorder.Events)
+
+	// the threshold trips the breaker, the failure is reported instead of returned
+	assert.NoError(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", failing))
+	assert.True(t, r.circuitBreakerOpen)
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Warning Degraded")
+	assert.Equal(t, circuitBreakerThreshold, calls)
+
+	// the open breaker skips the resource until its retry time
+	r.circuitBreakerOpen = false
+	assert.NoError(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", failing))
+	assert.True(t, r.circuitBreakerOpen)
+	assert.Equal(t, circuitBreakerThreshold, calls)
+
+	// a failed retry keeps the breaker open and is reported once
+	r.resourceRetryTimes["PrometheusRule/test"] = time.Now()
+	assert.NoError(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", failing))
+	assert.Equal(t, circuitBreakerThreshold+1, calls)
+	assert.True(t, r.resourceRetryTimes["PrometheusRule/test"].After(time.Now()))
+	assert.Empty(t, recorder.Events)
+
+	// other resources are tracked independently
+	assert.Error(t, r.reconcileWithCircuitBreaker("ConsolePlugin", failing))
+
+	// a successful retry resets the breaker
+	r.resourceRetryTimes["PrometheusRule/test"] = time.Now()
+	assert.NoError(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", func() error { return nil }))
+	assert.NotContains(t, r.resourceFailures, "PrometheusRule/test")
+	assert.NotContains(t, r.resourceRetryTimes, "PrometheusRule/test")
+	assert.Error(t, r.reconcileWithCircuitBreaker("PrometheusRule/test", failing))
+	assert.Equal(t, 1, r.resourceFailures["PrometheusRule/test"])
+}
+
+func TestOwnedByLabelOnManagedResources(t *testing.T) {
+	r := newSMSReconciler(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "monitoring-a"}})
+	r.operatorConfigMap.Data = map[string]string{prometheusRuleNamespacesKey: "monitoring-a"}
+	pvcRule, err := decodePrometheusRule(pvcPrometheusRules)
+	assert.NoError(t, err)
+
+	assert.NoError(t, r.reconcileWebhookService())
+	assert.NoError(t, r.reconcilePrometheusRule(pvcRule))
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName}))
+	assert.NoError(t, r.reconcileTrustedCABundleConfigMap(true))
+
+	ownedBy := client.MatchingLabels{OwnedByLabelKey: ManagedByLabelValue}
+	services := &corev1.ServiceList{}
+	assert.NoError(t, r.List(r.ctx, services, client.InNamespace(testNamespace), ownedBy))
+	assert.Len(t, services.Items, 1)
+
+	rules := &monitoringv1.PrometheusRuleList{}
+	assert.NoError(t, r.List(r.ctx, rules, ownedBy))
+	// the rule in the operator namespace and its mirror
+	assert.Len(t, rules.Items, 2)
+
+	sccs := &secv1.SecurityContextConstraintsList{}
+	assert.NoError(t, r.List(r.ctx, sccs, ownedBy))
+	assert.Len(t, sccs.Items, 1)
+
+	configMaps := &corev1.ConfigMapList{}
+	assert.NoError(t, r.List(r.ctx, configMaps, client.InNamespace(testNamespace), ownedBy))
+	assert.Len(t, configMaps.Items, 1)
+	assert.Equal(t, templates.TrustedCABundleConfigMapName, configMaps.Items[0].Name)
+}
+
+func TestSecurityContextConstraintsRecreatedAfterDeletion(t *testing.T) {
+	r := newSMSReconciler(t)
+	scc := &secv1.SecurityContextConstraints{}
+	scc.Name = templates.SCCName
+
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName}))
+	assert.NoError(t, r.get(scc))
+	assert.Equal(t, ManagedByLabelValue, scc.Labels[ManagedByLabelKey])
+
+	// the deletion of the labeled scc passes the watch predicate and enqueues a reconcile
+	assert.NoError(t, r.Delete(r.ctx, scc))
+	assert.True(t, kerrors.IsNotFound(r.get(scc)))
+	assert.True(t, isOwnedByOperatorConfigMap(scc))
+
+	foreignScc := &secv1.SecurityContextConstraints{ObjectMeta: metav1.ObjectMeta{Name: "other-scc"}}
+	assert.False(t, isOwnedByOperatorConfigMap(foreignScc))
+
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName}))
+	recreated := &secv1.SecurityContextConstraints{}
+	recreated.Name = templates.SCCName
+	assert.NoError(t, r.get(recreated))
+	assert.Contains(t, recreated.Users, "system:serviceaccount:"+testNamespace+":ceph-csi-rbd-nodeplugin-sa")
+}
+
+func TestSecurityContextConstraintsFollowEnabledDrivers(t *testing.T) {
+	r := newSMSReconciler(t)
+	scc := &secv1.SecurityContextConstraints{}
+	scc.Name = templates.SCCName
+	userFor := func(serviceAccount string) string {
+		return "system:serviceaccount:" + testNamespace + ":" + serviceAccount
+	}
+
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName, templates.CephFsDriverName}))
+	assert.NoError(t, r.get(scc))
+	assert.Contains(t, scc.Users, userFor("ceph-csi-rbd-nodeplugin-sa"))
+	assert.Contains(t, scc.Users, userFor("ceph-csi-cephfs-ctrlplugin-sa"))
+	assert.NotContains(t, scc.Users, userFor("ceph-csi-nfs-nodeplugin-sa"))
+
+	// users granted outside of the operator are kept
+	scc.Users = append(scc.Users, userFor("other-sa"))
+	assert.NoError(t, r.Update(r.ctx, scc))
+
+	// disabling one driver only drops its users
+	assert.NoError(t, r.reconcileSecurityContextConstraints([]string{templates.RBDDriverName}))
+	assert.NoError(t, r.get(scc))
+	assert.Contains(t, scc.Users, userFor("ceph-csi-rbd-ctrlplugin-sa"))
+	assert.NotContains(t, scc.Users, userFor("ceph-csi-cephfs-ctrlplugin-sa"))
+	assert.NotContains(t, scc.Users, userFor("ceph-csi-cephfs-nodeplugin-sa"))
+	assert.Contains(t, scc.Users, userFor("other-sa"))
+
+	// the scc is kept for the nvmeof plugins when no driver is enabled
+	assert.NoError(t, r.reconcileSecurityContextConstraints(nil))
+	assert.NoError(t, r.get(scc))
+	assert.ElementsMatch(t, []string{
+		userFor("ceph-csi-nvmeof-ctrlplugin-sa"),
+		userFor("ceph-csi-nvmeof-nodeplugin-sa"),
+		userFor("other-sa"),
+	}, scc.Users)
+}
+
+func TestSecurityContextConstraintsKeepRetainedNfsDriver(t *testing.T) {
+	pv := &corev1.PersistentVolume{
+		ObjectMeta: metav1.ObjectMeta{Name: "nfs-pv"},
+		Spec: corev1.PersistentVolumeSpec{PersistentVolumeSource: corev1.PersistentVolumeSource{
+			CSI: &corev1.CSIPersistentVolumeSource{Driver: templates.NfsDriverName},
+		}},
+	}
+	nfsDriver := &csiopv1.Driver{ObjectMeta: metav1.ObjectMeta{Name: templates.NfsDriverName, Namespace: testNamespace}}
+	r := newDelegatedCSIReconciler(t)
+	assert.NoError(t, snapapi.AddToScheme(r.Scheme))
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(
+			&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "owner-cm", Namespace: testNamespace, UID: "test-uid"}},
+			&configv1.ClusterVersion{
+				ObjectMeta: metav1.ObjectMeta{Name: clusterVersionName},
+				Status: configv1.ClusterVersionStatus{
+					History: []configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: fake418ClusterVersion}},
+				},
+			},
+			&configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     configv1.InfrastructureStatus{ControlPlaneTopology: configv1.HighlyAvailableTopologyMode},
+			},
+			fake418ImageSet.DeepCopy(),
+		).
+		WithIndex(&corev1.PersistentVolume{}, pvDriverIndexName, func(o client.Object) []string {
+			return []string{o.(*corev1.PersistentVolume).Spec.CSI.Driver}
+		}).
+		WithIndex(&snapapi.VolumeSnapshotContent{}, vscDriverIndexName, func(o client.Object) []string {
+			return []string{o.(*snapapi.VolumeSnapshotContent).Spec.Driver}
+		}).
+		Build()
+	assert.NoError(t, r.Create(r.ctx, pv))
+	assert.NoError(t, r.Create(r.ctx, nfsDriver))
+	delete(r.operatorConfigMap.Data, enableNfsDriverKey)
+
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.NoError(t, r.get(nfsDriver))
+	scc := &secv1.SecurityContextConstraints{}
+	scc.Name = templates.SCCName
+	assert.NoError(t, r.get(scc))
+	assert.Contains(t, scc.Users, "system:serviceaccount:"+testNamespace+":ceph-csi-nfs-nodeplugin-sa")
+
+	// once the volumes are gone, the driver and its scc users are removed
+	assert.NoError(t, r.Delete(r.ctx, pv))
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.True(t, kerrors.IsNotFound(r.get(nfsDriver)))
+	assert.NoError(t, r.get(scc))
+	assert.NotContains(t, scc.Users, "system:serviceaccount:"+testNamespace+":ceph-csi-nfs-nodeplugin-sa")
+}
+
+func TestGetCSIRolloutWindowWait(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.May, 1, hour, minute, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		name      string
+		window    string
+		now       time.Time
+		expected  time.Duration
+		expectErr bool
+	}{
+		{
+			name: "no window",
+			now:  at(12, 0),
+		},
+		{
+			name:   "inside the window",
+			window: "02:00-04:00",
+			now:    at(3, 0),
+		},
+		{
+			name:     "before the window",
+			window:   "02:00-04:00",
+			now:      at(1, 30),
+			expected: 30 * time.Minute,
+		},
+		{
+			name:     "after the window waits for the next day",
+			window:   "02:00-04:00",
+			now:      at(4, 0),
+			expected: 22 * time.Hour,
+		},
+		{
+			name:   "inside a window spanning midnight",
+			window: "22:00 - 02:00",
+			now:    at(1, 0),
+		},
+		{
+			name:     "outside a window spanning midnight",
+			window:   "22:00-02:00",
+			now:      at(12, 0),
+			expected: 10 * time.Hour,
+		},
+		{
+			name:      "missing end",
+			window:    "22:00",
+			expectErr: true,
+		},
+		{
+			name:      "invalid time",
+			window:    "25:00-02:00",
+			expectErr: true,
+		},
+		{
+			name:      "empty window",
+			window:    "02:00-02:00",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wait, err := getCSIRolloutWindowWait(tt.window, tt.now)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, wait)
+		})
+	}
+}
+
+func TestDeferCSIRollout(t *testing.T) {
+	for _, outsideWindow := range []bool{true, false} {
+		t.Run(fmt.Sprintf("outsideWindow=%t", outsideWindow), func(t *testing.T) {
+			existing := &csiopv1.Driver{
+				ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName, Namespace: testNamespace},
+				Spec:       csiopv1.DriverSpec{GenerateOMapInfo: ptr.To(false)},
+			}
+			r := newSMSReconciler(t, existing)
+			if outsideWindow {
+				r.csiRolloutWindowWait = time.Hour
+			}
+
+			driver := &csiopv1.Driver{}
+			driver.Name = existing.Name
+			driver.Namespace = existing.Namespace
+			assert.NoError(t, r.createOrUpdate(driver, r.deferCSIRollout(driver, func() error {
+				utils.AddLabel(driver, ManagedByLabelKey, ManagedByLabelValue)
+				driver.Spec.GenerateOMapInfo = ptr.To(true)
+				return nil
+			})))
+
+			actual := &csiopv1.Driver{}
+			assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(existing), actual))
+			// metadata changes are never deferred
+			assert.Equal(t, ManagedByLabelValue, actual.Labels[ManagedByLabelKey])
+			assert.Equal(t, outsideWindow, r.csiRolloutDeferred)
+			assert.Equal(t, !outsideWindow, ptr.Deref(actual.Spec.GenerateOMapInfo, false))
+		})
+	}
+
+	// resources other than the csi ones are rejected rather than panicking
+	r := newSMSReconciler(t)
+	r.csiRolloutWindowWait = time.Hour
+	configMap := r.operatorConfigMap.DeepCopy()
+	assert.Error(t, r.deferCSIRollout(configMap, func() error { return nil })())
+}
+
+func TestOperatorImageChangedPredicate(t *testing.T) {
+	newDeployment := func(namespace, image string, labels map[string]string) *appsv1.Deployment {
+		deployment := &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "ocs-client-operator-controller-manager", Namespace: namespace, Labels: labels},
+		}
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{{Name: "manager", Image: image}}
+		return deployment
+	}
+	operatorLabels := map[string]string{operatorDeploymentLabelKey: operatorDeploymentLabelValue}
+
+	tests := []struct {
+		name     string
+		old      *appsv1.Deployment
+		new      *appsv1.Deployment
+		expected bool
+	}{
+		{
+			name:     "operator image changed",
+			old:      newDeployment(testNamespace, "operator:v1", operatorLabels),
+			new:      newDeployment(testNamespace, "operator:v2", operatorLabels),
+			expected: true,
+		},
+		{
+			name: "operator image unchanged",
+			old:  newDeployment(testNamespace, "operator:v1", operatorLabels),
+			new:  newDeployment(testNamespace, "operator:v1", operatorLabels),
+		},
+		{
+			name: "other deployment image changed",
+			old:  newDeployment(testNamespace, "console:v1", nil),
+			new:  newDeployment(testNamespace, "console:v2", nil),
+		},
+		{
+			name: "operator deployment in another namespace",
+			old:  newDeployment("other-ns", "operator:v1", operatorLabels),
+			new:  newDeployment("other-ns", "operator:v2", operatorLabels),
+		},
+	}
+
+	p := newOperatorImageChangedPredicate(testNamespace)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, p.Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new}))
+			assert.False(t, p.Create(event.CreateEvent{Object: tt.new}))
+		})
+	}
+}
+
+func TestReportCSIOperatorConfigChange(t *testing.T) {
+	r := newSMSReconciler(t)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+	changeEvents := func() []string {
+		var changes []string
+		for {
+			select {
+			case event := <-recorder.Events:
+				changes = append(changes, event)
+			default:
+				return changes
+			}
+		}
+	}
+
+	spec := templates.CSIOperatorConfigSpec.DeepCopy()
+	// the first resolved config has nothing to compare against
+	assert.NoError(t, r.reportCSIOperatorConfigChange(spec))
+	assert.Empty(t, changeEvents())
+
+	assert.NoError(t, r.reportCSIOperatorConfigChange(spec.DeepCopy()))
+	assert.Empty(t, changeEvents())
+
+	spec.DriverSpecDefaults.GenerateOMapInfo = ptr.To(true)
+	spec.DriverSpecDefaults.ControllerPlugin.Replicas = ptr.To(int32(3))
+	assert.NoError(t, r.reportCSIOperatorConfigChange(spec))
+	changes := changeEvents()
+	assert.Len(t, changes, 1)
+	assert.Contains(t, changes[0], "Normal CSIConfigChanged")
+	assert.Contains(t, changes[0], "driverSpecDefaults.controllerPlugin.replicas, driverSpecDefaults.generateOMapInfo")
+}
+
+func TestRecordCSIOperatorConfigHistory(t *testing.T) {
+	r := newSMSReconciler(t)
+	getHistory := func() map[string]string {
+		history := &corev1.ConfigMap{}
+		err := r.Get(r.ctx, types.NamespacedName{Name: csiConfigHistoryConfigMapName, Namespace: testNamespace}, history)
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		assert.NoError(t, err)
+		return history.Data
+	}
+	assertLatestEntry := func(history map[string]string, spec *csiopv1.OperatorConfigSpec) {
+		keys := slices.Sorted(maps.Keys(history))
+		expected, err := json.Marshal(spec)
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(expected), history[keys[len(keys)-1]])
+	}
+
+	// no history is kept while the limit is unset
+	spec := templates.CSIOperatorConfigSpec.DeepCopy()
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec))
+	assert.Nil(t, getHistory())
+
+	r.operatorConfigMap.Data = map[string]string{csiConfigHistoryLimitKey: "2"}
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec))
+	history := getHistory()
+	assert.Len(t, history, 1)
+	assertLatestEntry(history, spec)
+
+	// an unchanged config isn't appended
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec.DeepCopy()))
+	assert.Equal(t, history, getHistory())
+
+	spec.DriverSpecDefaults.GenerateOMapInfo = ptr.To(true)
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec))
+	history = getHistory()
+	assert.Len(t, history, 2)
+	assertLatestEntry(history, spec)
+	oldestKey := slices.Sorted(maps.Keys(history))[0]
+
+	// the oldest entry is pruned at the bound
+	spec.DriverSpecDefaults.ControllerPlugin.Replicas = ptr.To(int32(3))
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec))
+	history = getHistory()
+	assert.Len(t, history, 2)
+	assert.NotContains(t, history, oldestKey)
+	assertLatestEntry(history, spec)
+
+	// lowering the limit prunes on the next reconcile
+	r.operatorConfigMap.Data[csiConfigHistoryLimitKey] = "1"
+	assert.NoError(t, r.recordCSIOperatorConfigHistory(spec))
+	assert.Len(t, getHistory(), 1)
+
+	for _, invalid := range []string{"-1", "many", "51"} {
+		r.operatorConfigMap.Data[csiConfigHistoryLimitKey] = invalid
+		assert.Error(t, r.recordCSIOperatorConfigHistory(spec))
+	}
+}
+
+func TestAnnotateConsoleNginxRootConfHash(t *testing.T) {
+	consoleDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: console.DeploymentName, Namespace: testNamespace},
+	}
+	r := newSMSReconciler(t, consoleDeployment)
+	r.consoleDeployment = consoleDeployment
+	getHash := func() string {
+		actual := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(consoleDeployment), actual))
+		return actual.Spec.Template.Annotations[nginxRootConfHashAnnotationKey]
+	}
+
+	rootConf, err := console.GetNginxRootConf(9001)
+	assert.NoError(t, err)
+	assert.NoError(t, r.annotateConsoleNginxRootConfHash(rootConf))
+	initialHash := getHash()
+	assert.Equal(t, utils.GetMD5Hash(rootConf), initialHash)
+
+	// an unchanged config keeps the pod template as is
+	assert.NoError(t, r.annotateConsoleNginxRootConfHash(rootConf))
+	assert.Equal(t, initialHash, getHash())
+
+	changedRootConf, err := console.GetNginxRootConf(9443)
+	assert.NoError(t, err)
+	assert.NoError(t, r.annotateConsoleNginxRootConfHash(changedRootConf))
+	assert.NotEqual(t, initialHash, getHash())
+	assert.Equal(t, utils.GetMD5Hash(changedRootConf), getHash())
+}
+
+func TestReconcileConsoleListenPort(t *testing.T) {
+	consoleDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: console.DeploymentName, Namespace: testNamespace},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name: console.DeploymentName,
+			Ports: []corev1.ContainerPort{
+				{Name: console.ListenPortName, ContainerPort: 9001, Protocol: corev1.ProtocolTCP},
+				{Name: "proxy", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+			},
+			LivenessProbe: &corev1.Probe{ProbeHandler: corev1.ProbeHandler{HTTPGet: &corev1.HTTPGetAction{
+				Path: "/plugin-manifest.json", Port: intstr.FromInt32(9001), Scheme: corev1.URISchemeHTTPS,
+			}}},
+		}}}}},
+	}
+	r := newSMSReconciler(t, consoleDeployment)
+	r.consoleDeployment = consoleDeployment
+	getContainer := func() corev1.Container {
+		actual := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(consoleDeployment), actual))
+		return actual.Spec.Template.Spec.Containers[0]
+	}
+
+	// the default port leaves the deployment alone
+	resourceVersion := consoleDeployment.ResourceVersion
+	assert.NoError(t, r.reconcileConsoleListenPort(9001))
+	assert.Equal(t, resourceVersion, r.consoleDeployment.ResourceVersion)
+
+	assert.NoError(t, r.reconcileConsoleListenPort(9002))
+	container := getContainer()
+	assert.Equal(t, int32(9002), container.Ports[0].ContainerPort)
+	assert.Equal(t, int32(8443), container.Ports[1].ContainerPort, "other ports should be left alone")
+	assert.Equal(t, intstr.FromInt32(9002), container.LivenessProbe.HTTPGet.Port)
+	assert.Equal(t, "/plugin-manifest.json", container.LivenessProbe.HTTPGet.Path)
+
+	// a probe referring to the port by name follows it without a patch
+	assert.NoError(t, r.get(r.consoleDeployment))
+	r.consoleDeployment.Spec.Template.Spec.Containers[0].LivenessProbe.HTTPGet.Port = intstr.FromString(console.ListenPortName)
+	assert.NoError(t, r.Update(r.ctx, r.consoleDeployment))
+	assert.NoError(t, r.reconcileConsoleListenPort(9003))
+	container = getContainer()
+	assert.Equal(t, int32(9003), container.Ports[0].ContainerPort)
+	assert.Equal(t, intstr.FromString(console.ListenPortName), container.LivenessProbe.HTTPGet.Port)
+}
+
+func TestReconcileConsoleReplicas(t *testing.T) {
+	consoleDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: console.DeploymentName, Namespace: testNamespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+	}
+	r := newSMSReconciler(t, consoleDeployment)
+	r.consoleDeployment = consoleDeployment
+	getReplicas := func() *int32 {
+		actual := &appsv1.Deployment{}
+		assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(consoleDeployment), actual))
+		return actual.Spec.Replicas
+	}
+
+	// unset leaves the deployment alone
+	resourceVersion := consoleDeployment.ResourceVersion
+	assert.NoError(t, r.reconcileConsoleReplicas())
+	assert.Equal(t, ptr.To(int32(1)), getReplicas())
+	assert.Equal(t, resourceVersion, r.consoleDeployment.ResourceVersion)
+
+	r.operatorConfigMap.Data = map[string]string{consolePluginReplicasKey: "3"}
+	assert.NoError(t, r.reconcileConsoleReplicas())
+	assert.Equal(t, ptr.To(int32(3)), getReplicas())
+
+	for _, invalid := range []string{"-1", "two"} {
+		r.operatorConfigMap.Data[consolePluginReplicasKey] = invalid
+		assert.Error(t, r.reconcileConsoleReplicas())
+	}
+	assert.Equal(t, ptr.To(int32(3)), getReplicas())
+}
+
+func TestCreateOrUpdateSkipsPausedResources(t *testing.T) {
+	rbdDriver := &csiopv1.Driver{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      templates.RBDDriverName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{pausedLabelKey: "true"},
+		},
+	}
+	cephFsDriver := &csiopv1.Driver{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.CephFsDriverName, Namespace: testNamespace},
+	}
+	r := newSMSReconciler(t, rbdDriver, cephFsDriver)
+
+	for _, name := range []string{templates.RBDDriverName, templates.CephFsDriverName} {
+		driver := &csiopv1.Driver{}
+		driver.Name = name
+		driver.Namespace = testNamespace
+		assert.NoError(t, r.createOrUpdate(driver, func() error {
+			driver.Spec.GenerateOMapInfo = ptr.To(true)
+			return nil
+		}))
+	}
+
+	actual := &csiopv1.Driver{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(rbdDriver), actual))
+	assert.Nil(t, actual.Spec.GenerateOMapInfo, "paused driver should not be updated")
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(cephFsDriver), actual))
+	assert.True(t, ptr.Deref(actual.Spec.GenerateOMapInfo, false))
+}
+
+func TestGetSupportedCSIImageVersions(t *testing.T) {
+	newImageSet := func(name, namespace, imageVersion string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{csiImagesConfigMapLabel: imageVersion},
+			},
+		}
+	}
+	r := newSMSReconciler(t,
+		newImageSet("csi-images-v4.18", testNamespace, "4.18"),
+		newImageSet("csi-images-v4.9", testNamespace, "4.9"),
+		newImageSet("csi-images-v4.18-hotfix", testNamespace, "4.18"),
+		newImageSet("csi-images-invalid", testNamespace, "latest"),
+		newImageSet("csi-images-v4.20", "other-ns", "4.20"),
+	)
+
+	supportedVersions, err := GetSupportedCSIImageVersions(r.ctx, r.Client, testNamespace)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4.9", "4.18"}, supportedVersions)
+
+	recorder := httptest.NewRecorder()
+	NewSupportedCSIImageVersionsHandler(r.Client, testNamespace).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/csi-image-versions", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `["4.9","4.18"]`, recorder.Body.String())
+}
+
+func TestEnsureConsolePluginOrDegrade(t *testing.T) {
+	// the console deployment is missing, so deploying the console fails
+	r := newSMSReconciler(t)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+
+	assert.Error(t, r.ensureConsolePluginOrDegrade())
+	select {
+	case event := <-recorder.Events:
+		assert.Contains(t, event, "Warning ConsolePluginDegraded")
+	default:
+		t.Fatal("expected a ConsolePluginDegraded event")
+	}
+}
+
+func TestGetCSIKubeletDirPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name: "operator default when unset",
+		},
+		{
+			name:     "custom kubelet dir",
+			value:    " /var/data/kubelet ",
+			expected: "/var/data/kubelet",
+		},
+		{
+			name:      "relative path is rejected",
+			value:     "var/lib/kubelet",
+			expectErr: true,
+		},
+		{
+			name:      "unclean path is rejected",
+			value:     "/var/lib/kubelet/",
+			expectErr: true,
+		},
+		{
+			name:      "root is rejected",
+			value:     "/",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{kubeletDirPathKey: tt.value}}
+
+			kubeletDirPath, err := r.getCSIKubeletDirPath()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, kubeletDirPath)
+		})
+	}
+}
+
+func TestMountClusterTrustBundle(t *testing.T) {
+	r := newSMSReconciler(t)
+	r.operatorConfigMap.Data = map[string]string{mountClusterTrustBundleKey: "true"}
+	assert.True(t, r.shouldMountClusterTrustBundle())
+
+	assert.NoError(t, r.reconcileTrustedCABundleConfigMap(true))
+	trustedCABundle := &corev1.ConfigMap{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.TrustedCABundleConfigMapName, Namespace: testNamespace}, trustedCABundle))
+	assert.Equal(t, "true", trustedCABundle.Labels[templates.TrustedCABundleInjectLabelKey])
+
+	nodePlugin := &csiopv1.NodePluginSpec{}
+	templates.SetTrustedCABundleVolume(&nodePlugin.PodCommonSpec, true)
+	templates.SetTrustedCABundleVolume(&nodePlugin.PodCommonSpec, true)
+	if assert.Len(t, nodePlugin.Volumes, 1, "volume should be added once") {
+		vol := nodePlugin.Volumes[0]
+		assert.Equal(t, templates.TrustedCABundleConfigMapName, vol.Volume.ConfigMap.Name)
+		assert.Equal(t, "/etc/pki/ca-trust/extracted/pem", vol.Mount.MountPath)
+		assert.True(t, vol.Mount.ReadOnly)
+	}
+
+	templates.SetTrustedCABundleVolume(&nodePlugin.PodCommonSpec, false)
+	assert.Empty(t, nodePlugin.Volumes)
+
+	r.operatorConfigMap.Data = nil
+	assert.False(t, r.shouldMountClusterTrustBundle())
+	assert.NoError(t, r.reconcileTrustedCABundleConfigMap(false))
+	err := r.Get(r.ctx, types.NamespacedName{Name: templates.TrustedCABundleConfigMapName, Namespace: testNamespace}, trustedCABundle)
+	assert.True(t, kerrors.IsNotFound(err))
+}
+
+func TestApplyLabelsIgnoresReservedKeys(t *testing.T) {
+	r := newFakeConfigMapReconciler(t)
+	var logged []string
+	r.log = funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	objMeta := &metav1.ObjectMeta{
+		Name:   "rule",
+		Labels: map[string]string{ManagedByLabelKey: ManagedByLabelValue, "existing": "kept"},
+	}
+	r.applyLabels("tenant: team-a\n"+ManagedByLabelKey+": someone-else", objMeta)
+
+	assert.Equal(t, map[string]string{
+		ManagedByLabelKey: ManagedByLabelValue,
+		"existing":        "kept",
+		"tenant":          "team-a",
+	}, objMeta.Labels)
+	if assert.Len(t, logged, 1) {
+		assert.Contains(t, logged[0], "reserved key")
+		assert.Contains(t, logged[0], ManagedByLabelKey)
+	}
+
+	// the reserved key is reported once
+	r.applyLabels("tenant: team-a\n"+ManagedByLabelKey+": someone-else", objMeta)
+	assert.Len(t, logged, 1)
+}
+
+func TestApplyLabelsPrunesRemovedKeys(t *testing.T) {
+	r := newFakeConfigMapReconciler(t)
+
+	objMeta := &metav1.ObjectMeta{
+		Name:   "rule",
+		Labels: map[string]string{ManagedByLabelKey: ManagedByLabelValue, "existing": "kept"},
+	}
+	r.applyLabels("tenant: team-a\nteam: storage", objMeta)
+	assert.Equal(t, "team,tenant", objMeta.Annotations[appliedLabelsAnnotationKey])
+
+	r.applyLabels("tenant: team-b", objMeta)
+	assert.Equal(t, map[string]string{
+		ManagedByLabelKey: ManagedByLabelValue,
+		"existing":        "kept",
+		"tenant":          "team-b",
+	}, objMeta.Labels)
+	assert.Equal(t, "tenant", objMeta.Annotations[appliedLabelsAnnotationKey])
+
+	r.applyLabels("", objMeta)
+	assert.Equal(t, map[string]string{
+		ManagedByLabelKey: ManagedByLabelValue,
+		"existing":        "kept",
+	}, objMeta.Labels)
+	assert.NotContains(t, objMeta.Annotations, appliedLabelsAnnotationKey)
+}
+
+func TestGetRBDDefaultFsTypeExtraArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string][]string
+		expectErr bool
+	}{
+		{
+			name: "provisioner default when unset",
+		},
+		{
+			name:  "xfs",
+			value: " xfs ",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--default-fstype=xfs"},
+			},
+		},
+		{
+			name:      "unsupported filesystem is rejected",
+			value:     "ntfs",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{rbdDefaultFsTypeKey: tt.value}}
+
+			args, err := r.getRBDDefaultFsTypeExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestGetCSIVolumeAttributesClassExtraArgs(t *testing.T) {
+	enabledArgs := map[string][]string{
+		templates.ProvisionerContainerName: {"--feature-gates=VolumeAttributesClass=true"},
+		templates.ResizerContainerName:     {"--feature-gates=VolumeAttributesClass=true"},
+	}
+	tests := []struct {
+		name         string
+		value        string
+		apiAvailable bool
+		expected     map[string][]string
+		expectErr    bool
+	}{
+		{
+			name:         "disabled when unset",
+			apiAvailable: true,
+		},
+		{
+			name:         "enabled with the api served",
+			value:        "true",
+			apiAvailable: true,
+			expected:     enabledArgs,
+		},
+		{
+			name:  "enabled without the api served is skipped",
+			value: "true",
+		},
+		{
+			name:         "invalid value is rejected",
+			value:        "maybe",
+			apiAvailable: true,
+			expectErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.AvailableCrds = map[string]bool{VolumeAttributesClassResourceName: tt.apiAvailable}
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiEnableVolumeAttributesClassKey: tt.value}}
+
+			args, err := r.getCSIVolumeAttributesClassExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestGetCSIAttacherTimeoutExtraArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string][]string
+		expectErr bool
+	}{
+		{
+			name: "attacher default when unset",
+		},
+		{
+			name:  "configured timeout",
+			value: " 2m ",
+			expected: map[string][]string{
+				templates.AttacherContainerName: {"--timeout=2m0s"},
+			},
+		},
+		{
+			name:      "non positive timeout is rejected",
+			value:     "0s",
+			expectErr: true,
+		},
+		{
+			name:      "invalid duration is rejected",
+			value:     "2 minutes",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiAttacherTimeoutKey: tt.value}}
+
+			args, err := r.getCSIAttacherTimeoutExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestGetCSIProvisionerRetryIntervalMaxExtraArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  map[string][]string
+		expectErr bool
+	}{
+		{
+			name: "provisioner default when unset",
+		},
+		{
+			name:  "configured retry interval max",
+			value: "10m",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--retry-interval-max=10m0s"},
+			},
+		},
+		{
+			name:      "negative duration is rejected",
+			value:     "-1m",
+			expectErr: true,
+		},
+		{
+			name:      "invalid duration is rejected",
+			value:     "ten minutes",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{csiProvisionerRetryIntervalMaxKey: tt.value}}
+
+			args, err := r.getCSIProvisionerRetryIntervalMaxExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestGetCSIVolumeNameExtraArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		prefix     string
+		uuidLength string
+		expected   map[string][]string
+		expectErr  bool
+	}{
+		{
+			name: "provisioner defaults when unset",
+		},
+		{
+			name:   "prefix only",
+			prefix: " cluster-a ",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--volume-name-prefix=cluster-a"},
+			},
+		},
+		{
+			name:       "prefix and uuid length",
+			prefix:     "cluster-a",
+			uuidLength: "16",
+			expected: map[string][]string{
+				templates.ProvisionerContainerName: {"--volume-name-prefix=cluster-a", "--volume-name-uuid-length=16"},
+			},
+		},
+		{
+			name:      "prefix that is not dns safe is rejected",
+			prefix:    "Cluster_A",
+			expectErr: true,
+		},
+		{
+			name:       "non positive uuid length is rejected",
+			uuidLength: "0",
+			expectErr:  true,
+		},
+		{
+			name:       "non numeric uuid length is rejected",
+			uuidLength: "short",
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeConfigMapReconciler(t)
+			r.operatorConfigMap = &corev1.ConfigMap{Data: map[string]string{
+				csiVolumeNamePrefixKey:     tt.prefix,
+				csiVolumeNameUUIDLengthKey: tt.uuidLength,
+			}}
+
+			args, err := r.getCSIVolumeNameExtraArgs()
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, args)
+		})
+	}
+}
+
+func TestManualReconcileHandler(t *testing.T) {
+	trigger := make(chan event.GenericEvent, 1)
+	h := NewManualReconcileHandler(trigger, testNamespace)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/reconcile", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Empty(t, trigger)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/reconcile", nil))
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+
+	// a reconcile that is already pending isn't requested again
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/reconcile", nil))
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	q := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+	defer q.ShutDown()
+	enqueue := &handler.EnqueueRequestForObject{}
+	for len(trigger) > 0 {
+		enqueue.Generic(context.Background(), <-trigger, q)
+	}
+	assert.Equal(t, 1, q.Len())
+	item, _ := q.Get()
+	assert.Equal(t, types.NamespacedName{Name: operatorConfigMapName, Namespace: testNamespace}, item.NamespacedName)
+	q.Done(item)
+}
+
+func TestReconcileCSIProvisionerPDBs(t *testing.T) {
+	selector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": templates.RBDDriverName + csiCtrlPluginSuffix}}
+	rbdCtrlPlugin := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiCtrlPluginSuffix, Namespace: testNamespace},
+		Spec:       appsv1.DeploymentSpec{Selector: selector},
+	}
+	r := newSMSReconciler(t)
+	// owned resources are matched against the operator configmap by name
+	r.operatorConfigMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: operatorConfigMapName, Namespace: testNamespace, UID: "test-uid"}}
+	deletes := 0
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(r.operatorConfigMap, rbdCtrlPlugin).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+				deletes++
+				return c.Delete(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	r.operatorConfigMap.Data = map[string]string{createCSIPDBKey: "true"}
+
+	getPDB := func(driverName string) (*policyv1.PodDisruptionBudget, error) {
+		pdb := &policyv1.PodDisruptionBudget{}
+		err := r.Get(r.ctx, types.NamespacedName{Name: driverName + csiCtrlPluginSuffix, Namespace: testNamespace}, pdb)
+		return pdb, err
+	}
+
+	assert.NoError(t, r.reconcileCSIProvisionerPDBs([]string{templates.RBDDriverName, templates.CephFsDriverName}))
+	pdb, err := getPDB(templates.RBDDriverName)
+	if assert.NoError(t, err) {
+		assert.Equal(t, selector, pdb.Spec.Selector)
+		assert.Nil(t, pdb.Spec.MinAvailable)
+		assert.Equal(t, intstr.FromInt32(1), *pdb.Spec.MaxUnavailable)
+		assert.True(t, isOwnedByOperatorConfigMap(pdb))
+	}
+	// no pdb until the controller plugin is deployed
+	_, err = getPDB(templates.CephFsDriverName)
+	assert.True(t, kerrors.IsNotFound(err))
+
+	r.operatorConfigMap.Data = nil
+	assert.NoError(t, r.reconcileCSIProvisionerPDBs([]string{templates.RBDDriverName, templates.CephFsDriverName}))
+	_, err = getPDB(templates.RBDDriverName)
+	assert.True(t, kerrors.IsNotFound(err))
+	assert.Equal(t, 1, deletes, "only the existing pdb should be deleted")
+
+	// missing pdbs aren't deleted again
+	assert.NoError(t, r.reconcileCSIProvisionerPDBs([]string{templates.RBDDriverName, templates.CephFsDriverName}))
+	assert.Equal(t, 1, deletes)
+}
+
+func TestReconcileCSIResourceServerSideApply(t *testing.T) {
+	r := newSMSReconciler(t)
+	// owned resources are matched against the operator configmap by name
+	r.operatorConfigMap = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: operatorConfigMapName, Namespace: testNamespace, UID: "test-uid"}}
+	r.Client = newFakeClientBuilder(r.Scheme).WithObjects(r.operatorConfigMap).WithReturnManagedFields().Build()
+	r.operatorConfigMap.Data = map[string]string{csiServerSideApplyKey: "true"}
+
+	// another manager owns the node plugin annotations
+	existing := &csiopv1.Driver{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.CephFsDriverName, Namespace: testNamespace},
+		Spec: csiopv1.DriverSpec{
+			NodePlugin: &csiopv1.NodePluginSpec{
+				PodCommonSpec: csiopv1.PodCommonSpec{Annotations: map[string]string{"injected": "true"}},
+			},
+		},
+	}
+	assert.NoError(t, r.Create(r.ctx, existing, client.FieldOwner("someone-else")))
+
+	driver := &csiopv1.Driver{}
+	driver.Name = templates.CephFsDriverName
+	driver.Namespace = testNamespace
+	assert.NoError(t, r.reconcileCSIResource(driver, func() error {
+		driver.Spec.ControllerPlugin = &csiopv1.ControllerPluginSpec{HostNetwork: ptr.To(true)}
+		return r.own(driver)
+	}))
+
+	applied := &csiopv1.Driver{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(driver), applied))
+	assert.True(t, ptr.Deref(applied.Spec.ControllerPlugin.HostNetwork, false))
+	assert.Equal(t, "true", applied.Spec.NodePlugin.Annotations["injected"], "fields of other managers should survive")
+	assert.True(t, isOwnedByOperatorConfigMap(applied))
+	assert.True(t, slices.ContainsFunc(applied.ManagedFields, func(entry metav1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManagerName && entry.Operation == metav1.ManagedFieldsOperationApply
+	}))
+
+	// spec changes are deferred outside of the rollout window
+	r.csiRolloutWindowWait = time.Hour
+	driver = &csiopv1.Driver{}
+	driver.Name = templates.CephFsDriverName
+	driver.Namespace = testNamespace
+	assert.NoError(t, r.reconcileCSIResource(driver, func() error {
+		driver.Spec.ControllerPlugin = &csiopv1.ControllerPluginSpec{HostNetwork: ptr.To(false)}
+		return r.own(driver)
+	}))
+	assert.True(t, r.csiRolloutDeferred)
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(driver), applied))
+	assert.True(t, ptr.Deref(applied.Spec.ControllerPlugin.HostNetwork, false))
+}
+
+func TestReconcileCSIResourceMigratesManagedFields(t *testing.T) {
+	r := newSMSReconciler(t)
+	r.Client = newFakeClientBuilder(r.Scheme).WithObjects(r.operatorConfigMap).WithReturnManagedFields().Build()
+	var logged []string
+	r.log = funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	// the driver was updated by the operator before server side apply was enabled
+	existing := &csiopv1.Driver{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.CephFsDriverName, Namespace: testNamespace},
+		Spec: csiopv1.DriverSpec{
+			ControllerPlugin: &csiopv1.ControllerPluginSpec{HostNetwork: ptr.To(true)},
+			NodePlugin: &csiopv1.NodePluginSpec{
+				PodCommonSpec: csiopv1.PodCommonSpec{Annotations: map[string]string{"dropped": "true"}},
+			},
+		},
+	}
+	assert.NoError(t, r.Create(r.ctx, existing, client.FieldOwner(fieldManagerName)))
+
+	r.operatorConfigMap.Data = map[string]string{csiServerSideApplyKey: "true"}
+	reconcileDriver := func() {
+		driver := &csiopv1.Driver{}
+		driver.Name = templates.CephFsDriverName
+		driver.Namespace = testNamespace
+		assert.NoError(t, r.reconcileCSIResource(driver, func() error {
+			driver.Spec.ControllerPlugin = &csiopv1.ControllerPluginSpec{HostNetwork: ptr.To(true)}
+			return r.own(driver)
+		}))
+	}
+	reconcileDriver()
+
+	applied := &csiopv1.Driver{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(existing), applied))
+	assert.Nil(t, applied.Spec.NodePlugin, "fields dropped from the desired state should be removed")
+	assert.False(t, slices.ContainsFunc(applied.ManagedFields, func(entry metav1.ManagedFieldsEntry) bool {
+		return entry.Manager == fieldManagerName && entry.Operation == metav1.ManagedFieldsOperationUpdate
+	}))
+	assert.NotEmpty(t, applied.Annotations[appliedHashAnnotationKey])
+
+	// an unchanged desired state isn't applied again
+	logged = nil
+	reconcileDriver()
+	unchanged := &csiopv1.Driver{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(existing), unchanged))
+	assert.Equal(t, applied.ResourceVersion, unchanged.ResourceVersion)
+	assert.Empty(t, logged)
+}
+
+func TestIsSubsetOf(t *testing.T) {
+	value := map[string]any{
+		"controllerPlugin": map[string]any{"hostNetwork": true, "replicas": int64(2)},
+		"nodePlugin":       map[string]any{"annotations": map[string]any{"injected": "true"}},
+		"log":              []any{"a", "b"},
+	}
+	assert.True(t, isSubsetOf(map[string]any{}, value))
+	assert.True(t, isSubsetOf(map[string]any{"controllerPlugin": map[string]any{"hostNetwork": true}}, value))
+	assert.True(t, isSubsetOf(map[string]any{"nodePlugin": map[string]any{}, "missing": nil}, value))
+	assert.False(t, isSubsetOf(map[string]any{"controllerPlugin": map[string]any{"hostNetwork": false}}, value))
+	assert.False(t, isSubsetOf(map[string]any{"missing": map[string]any{"field": "x"}}, value))
+	// lists are atomic
+	assert.False(t, isSubsetOf(map[string]any{"log": []any{"a"}}, value))
+}
+
+// newDelegatedCSIReconciler returns a reconciler with the cluster state reconcileDelegatedCSI depends on. The nfs
+// driver is enabled as checking for its volumes before removing it requires field indexes.
+func newDelegatedCSIReconciler(t *testing.T, objs ...client.Object) OperatorConfigMapReconciler {
+	clusterVersion := &configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterVersionName},
+		Status: configv1.ClusterVersionStatus{
+			History: []configv1.UpdateHistory{{State: configv1.CompletedUpdate, Version: fake418ClusterVersion}},
+		},
+	}
+	infrastructure := &configv1.Infrastructure{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Status:     configv1.InfrastructureStatus{ControlPlaneTopology: configv1.HighlyAvailableTopologyMode},
+	}
+	r := newSMSReconciler(t, append([]client.Object{clusterVersion, infrastructure, fake418ImageSet.DeepCopy()}, objs...)...)
+	r.operatorConfigMap.Data = map[string]string{enableNfsDriverKey: "true"}
+	return r
+}
+
+func TestCSIKubeletDirPath(t *testing.T) {
+	r := newDelegatedCSIReconciler(t)
+	getKubeletDirPath := func() string {
+		csiOperatorConfig := &csiopv1.OperatorConfig{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+		return csiOperatorConfig.Spec.DriverSpecDefaults.NodePlugin.KubeletDirPath
+	}
+
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.Equal(t, templates.CSIOperatorConfigSpec.DriverSpecDefaults.NodePlugin.KubeletDirPath, getKubeletDirPath())
+
+	// ceph-csi-operator derives the registration path of the node driver registrar from the kubelet dir
+	r.operatorConfigMap.Data[kubeletDirPathKey] = "/var/data/kubelet"
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.Equal(t, "/var/data/kubelet", getKubeletDirPath())
+}
+
+func TestCSIImageOverrides(t *testing.T) {
+	r := newDelegatedCSIReconciler(t)
+	imageSet := fake418ImageSet.DeepCopy()
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(imageSet), imageSet))
+	imageSet.Data = map[string]string{
+		imageSetPluginKey:    "quay.io/cephcsi/cephcsi:v3.15.0",
+		imageSetRegistrarKey: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.15.0",
+		"provisioner":        "registry.k8s.io/sig-storage/csi-provisioner:v6.0.0",
+	}
+	assert.NoError(t, r.Update(r.ctx, imageSet))
+	assert.NoError(t, r.Create(r.ctx, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-service-ca.crt", Namespace: testNamespace},
+	}))
+	r.operatorConfigMap.Data[enableRbdDriverKey] = "true"
+
+	getImageSets := func() (string, *corev1.LocalObjectReference) {
+		csiOperatorConfig := &csiopv1.OperatorConfig{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+		rbdDriver := &csiopv1.Driver{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.RBDDriverName, Namespace: testNamespace}, rbdDriver))
+		return csiOperatorConfig.Spec.DriverSpecDefaults.ImageSet.Name, rbdDriver.Spec.ImageSet
+	}
+	getImages := func(name string) map[string]string {
+		cm := &corev1.ConfigMap{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, cm))
+		return cm.Data
+	}
+
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	defaultsImageSet, rbdImageSet := getImageSets()
+	assert.Equal(t, imageSet.Name, defaultsImageSet)
+	assert.Nil(t, rbdImageSet)
+
+	r.operatorConfigMap.Data[csiRegistrarImageKey] = "mirror-a.example.com/csi-node-driver-registrar:v2.15.0"
+	r.operatorConfigMap.Data[csiRBDPluginImageKey] = " mirror-b.example.com/cephcsi:v3.15.0 "
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	defaultsImageSet, rbdImageSet = getImageSets()
+	assert.Equal(t, csiImageSetConfigMapName, defaultsImageSet)
+	assert.Equal(t, map[string]string{
+		imageSetPluginKey:    "quay.io/cephcsi/cephcsi:v3.15.0",
+		imageSetRegistrarKey: "mirror-a.example.com/csi-node-driver-registrar:v2.15.0",
+		"provisioner":        "registry.k8s.io/sig-storage/csi-provisioner:v6.0.0",
+	}, getImages(defaultsImageSet))
+	assert.Equal(t, &corev1.LocalObjectReference{Name: csiRBDImageSetConfigMapName}, rbdImageSet)
+	assert.Equal(t, map[string]string{
+		imageSetPluginKey:    "mirror-b.example.com/cephcsi:v3.15.0",
+		imageSetRegistrarKey: "mirror-a.example.com/csi-node-driver-registrar:v2.15.0",
+		"provisioner":        "registry.k8s.io/sig-storage/csi-provisioner:v6.0.0",
+	}, getImages(rbdImageSet.Name))
+
+	r.operatorConfigMap.Data[csiRBDPluginImageKey] = "mirror-b.example.com/ceph csi"
+	assert.Error(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+
+	// unsetting the overrides restores the selected imageset
+	delete(r.operatorConfigMap.Data, csiRegistrarImageKey)
+	delete(r.operatorConfigMap.Data, csiRBDPluginImageKey)
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	defaultsImageSet, rbdImageSet = getImageSets()
+	assert.Equal(t, imageSet.Name, defaultsImageSet)
+	assert.Nil(t, rbdImageSet)
+	for _, name := range []string{csiImageSetConfigMapName, csiRBDImageSetConfigMapName} {
+		err := r.Get(r.ctx, types.NamespacedName{Name: name, Namespace: testNamespace}, &corev1.ConfigMap{})
+		assert.True(t, kerrors.IsNotFound(err))
+	}
+}
+
+func TestReportCSIImageUpgradePlan(t *testing.T) {
+	imageSet := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "csi-images-v4.22", Namespace: testNamespace},
+		Data: map[string]string{
+			imageSetPluginKey:    "quay.io/cephcsi/cephcsi:v3.15.0",
+			imageSetRegistrarKey: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.15.0",
+			"provisioner":        "registry.k8s.io/sig-storage/csi-provisioner:v6.0.0",
+		},
+	}
+	ctrlPlugin := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiCtrlPluginSuffix, Namespace: testNamespace},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: templates.RBDPluginContainerName, Image: "quay.io/cephcsi/cephcsi:v3.15.0"},
+			{Name: templates.ProvisionerContainerName, Image: "registry.k8s.io/sig-storage/csi-provisioner:v5.3.0"},
+			{Name: "liveness-prometheus", Image: "quay.io/cephcsi/cephcsi:v3.14.0"},
+		}}}},
+	}
+	nodePlugin := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiNodePluginSuffix, Namespace: testNamespace},
+		Spec: appsv1.DaemonSetSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{
+			{Name: templates.RBDPluginContainerName, Image: "quay.io/cephcsi/cephcsi:v3.15.0"},
+			{Name: "driver-registrar", Image: "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.14.0"},
+		}}}},
+	}
+	r := newSMSReconciler(t, imageSet, ctrlPlugin, nodePlugin)
+	recorder := events.NewFakeRecorder(10)
+	r.Recorder = recorder
+	var logged []string
+	r.log = funcr.New(func(prefix, args string) {
+		logged = append(logged, args)
+	}, funcr.Options{})
+
+	assert.NoError(t, r.reportCSIImageUpgradePlan(imageSet.Name, ""))
+	assert.Len(t, logged, 1)
+	assert.Contains(t, logged[0], "csi images are about to be upgraded")
+	assert.Contains(t, logged[0], ctrlPlugin.Name+"/csi-provisioner: "+
+		"registry.k8s.io/sig-storage/csi-provisioner:v5.3.0 -> registry.k8s.io/sig-storage/csi-provisioner:v6.0.0")
+	assert.Contains(t, logged[0], nodePlugin.Name+"/driver-registrar: "+
+		"registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.14.0 -> registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.15.0")
+	assert.NotContains(t, logged[0], templates.RBDPluginContainerName)
+	assert.NotContains(t, logged[0], "liveness-prometheus")
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "Normal CSIImagesUpgrade")
+
+	// the plan is not reported again for the same imagesets
+	logged = nil
+	assert.NoError(t, r.reportCSIImageUpgradePlan(imageSet.Name, ""))
+	assert.Empty(t, logged)
+	assert.Empty(t, recorder.Events)
+
+	// the rbd imageset applies to the rbd plugins
+	rbdImageSet := imageSet.DeepCopy()
+	rbdImageSet.ObjectMeta = metav1.ObjectMeta{Name: csiRBDImageSetConfigMapName, Namespace: testNamespace}
+	rbdImageSet.Data[imageSetPluginKey] = "mirror.example.com/cephcsi:v3.15.0"
+	assert.NoError(t, r.Create(r.ctx, rbdImageSet))
+	logged = nil
+	assert.NoError(t, r.reportCSIImageUpgradePlan(imageSet.Name, rbdImageSet.Name))
+	assert.Len(t, logged, 1)
+	assert.Contains(t, logged[0], nodePlugin.Name+"/csi-rbdplugin: "+
+		"quay.io/cephcsi/cephcsi:v3.15.0 -> mirror.example.com/cephcsi:v3.15.0")
+	<-recorder.Events
+
+	// nothing is reported once the plugins run the desired images
+	ctrlPlugin.Spec.Template.Spec.Containers[1].Image = "registry.k8s.io/sig-storage/csi-provisioner:v6.0.0"
+	assert.NoError(t, r.Update(r.ctx, ctrlPlugin))
+	nodePlugin.Spec.Template.Spec.Containers[1].Image = "registry.k8s.io/sig-storage/csi-node-driver-registrar:v2.15.0"
+	assert.NoError(t, r.Update(r.ctx, nodePlugin))
+	logged = nil
+	assert.NoError(t, r.reportCSIImageUpgradePlan(imageSet.Name, ""))
+	assert.Empty(t, logged)
+	assert.Empty(t, recorder.Events)
+}
+
+func TestSuspendCSIProvisioners(t *testing.T) {
+	r := newDelegatedCSIReconciler(t)
+	getReplicas := func() *int32 {
+		csiOperatorConfig := &csiopv1.OperatorConfig{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+		return csiOperatorConfig.Spec.DriverSpecDefaults.ControllerPlugin.Replicas
+	}
+
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.Equal(t, templates.CSIOperatorConfigSpec.DriverSpecDefaults.ControllerPlugin.Replicas, getReplicas())
+
+	r.operatorConfigMap.Data[csiProvisionersSuspendedKey] = "true"
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.Equal(t, ptr.To(int32(0)), getReplicas())
+
+	r.operatorConfigMap.Data[csiProvisionersSuspendedKey] = "false"
+	assert.NoError(t, r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{}))
+	assert.Equal(t, templates.CSIOperatorConfigSpec.DriverSpecDefaults.ControllerPlugin.Replicas, getReplicas())
+}
+
+func TestCSINodePluginUpdateStrategy(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  *appsv1.DaemonSetUpdateStrategy
+		expectErr bool
+	}{
+		{
+			name: "ceph-csi-operator default when unset",
+		},
+		{
+			name:     "on delete",
+			value:    "OnDelete",
+			expected: &appsv1.DaemonSetUpdateStrategy{Type: appsv1.OnDeleteDaemonSetStrategyType},
+		},
+		{
+			name:     "rolling update",
+			value:    " RollingUpdate ",
+			expected: &appsv1.DaemonSetUpdateStrategy{Type: appsv1.RollingUpdateDaemonSetStrategyType},
+		},
+		{
+			name:      "unknown strategy is rejected",
+			value:     "Recreate",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newDelegatedCSIReconciler(t)
+			r.operatorConfigMap.Data[csiNodePluginUpdateStrategyKey] = tt.value
+
+			err := r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{})
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			// the node plugin daemonsets of all drivers are rendered from the defaults
+			csiOperatorConfig := &csiopv1.OperatorConfig{}
+			assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+			assert.Equal(t, tt.expected, csiOperatorConfig.Spec.DriverSpecDefaults.NodePlugin.UpdateStrategy)
+		})
+	}
+}
+
+func TestCSIArchitectureAffinity(t *testing.T) {
+	tests := []struct {
+		name          string
+		value         string
+		expectedArchs []string
+		expectErr     bool
+	}{
+		{
+			name: "scheduled on all nodes when unset",
+		},
+		{
+			name:          "single architecture",
+			value:         "amd64",
+			expectedArchs: []string{"amd64"},
+		},
+		{
+			name:          "multiple architectures are de-duplicated",
+			value:         " amd64, arm64,amd64 ",
+			expectedArchs: []string{"amd64", "arm64"},
+		},
+		{
+			name:      "invalid architecture is rejected",
+			value:     "amd64,arm 64",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newDelegatedCSIReconciler(t)
+			r.operatorConfigMap.Data[csiSupportedArchitecturesKey] = tt.value
+
+			err := r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{})
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			csiOperatorConfig := &csiopv1.OperatorConfig{}
+			assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CSIOperatorConfigName, Namespace: testNamespace}, csiOperatorConfig))
+			driverSpecDefaults := csiOperatorConfig.Spec.DriverSpecDefaults
+			if tt.expectedArchs == nil {
+				assert.Nil(t, driverSpecDefaults.ControllerPlugin.Affinity)
+				assert.Nil(t, driverSpecDefaults.NodePlugin.Affinity)
+				return
+			}
+			for _, affinity := range []*corev1.Affinity{driverSpecDefaults.ControllerPlugin.Affinity, driverSpecDefaults.NodePlugin.Affinity} {
+				assert.NotNil(t, affinity)
+				terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+				assert.Equal(t, []corev1.NodeSelectorRequirement{{
+					Key:      corev1.LabelArchStable,
+					Operator: corev1.NodeSelectorOpIn,
+					Values:   tt.expectedArchs,
+				}}, terms[0].MatchExpressions)
+			}
+		})
+	}
+}
+
+func TestCephFsMounter(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  csiopv1.CephFsClientType
+		expectErr bool
+	}{
+		{
+			name: "ceph-csi default when unset",
+		},
+		{
+			name:     "kernel client",
+			value:    "kernel",
+			expected: csiopv1.KernelCephFsClient,
+		},
+		{
+			name:     "autodetect client",
+			value:    " autodetect ",
+			expected: csiopv1.AutoDetectCephFsClient,
+		},
+		{
+			name:      "fuse can't be forced",
+			value:     "fuse",
+			expectErr: true,
+		},
+		{
+			name:      "unknown client is rejected",
+			value:     "nfs",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newDelegatedCSIReconciler(t)
+			r.operatorConfigMap.Data[enableCephFsDriverKey] = "true"
+			r.operatorConfigMap.Data[cephFsMounterKey] = tt.value
+
+			err := r.reconcileDelegatedCSI(&v1alpha1.StorageClientList{})
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			cephFsDriver := &csiopv1.Driver{}
+			assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: templates.CephFsDriverName, Namespace: testNamespace}, cephFsDriver))
+			assert.Equal(t, tt.expected, cephFsDriver.Spec.CephFsClientType)
+		})
+	}
+}
+
+func TestGetHealthSummary(t *testing.T) {
+	scheme := newFakeScheme(t)
+	assert.NoError(t, consolev1.AddToScheme(scheme))
+	rbdCtrlPlugin := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiCtrlPluginSuffix, Namespace: testNamespace},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(int32(2))},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: 2},
+	}
+	rbdNodePlugin := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName + csiNodePluginSuffix, Namespace: testNamespace},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, NumberReady: 1},
+	}
+	consoleDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: console.DeploymentName, Namespace: testNamespace},
+	}
+	kubeClient := newFakeClientBuilder(scheme).WithObjects(
+		rbdCtrlPlugin,
+		rbdNodePlugin,
+		&storagev1.CSIDriver{ObjectMeta: metav1.ObjectMeta{Name: templates.RBDDriverName}},
+		consoleDeployment,
+		&secv1.SecurityContextConstraints{ObjectMeta: metav1.ObjectMeta{Name: templates.SCCName}},
+		&consolev1.ConsolePlugin{ObjectMeta: metav1.ObjectMeta{Name: console.PluginName}},
+	).Build()
+
+	summary, err := GetHealthSummary(context.Background(), kubeClient, testNamespace)
+	assert.NoError(t, err)
+	byName := map[string]ResourceHealth{}
+	for _, health := range summary {
+		byName[health.Kind+"/"+health.Name] = health
+	}
+
+	assert.Equal(t, ResourceHealth{
+		Kind: "Deployment", Name: rbdCtrlPlugin.Name, Present: true, Ready: ptr.To(true), Message: "2/2 replicas available",
+	}, byName["Deployment/"+rbdCtrlPlugin.Name])
+	assert.Equal(t, ResourceHealth{
+		Kind: "DaemonSet", Name: rbdNodePlugin.Name, Present: true, Ready: ptr.To(false), Message: "1/3 pods ready",
+	}, byName["DaemonSet/"+rbdNodePlugin.Name])
+	assert.True(t, byName["CSIDriver/"+templates.RBDDriverName].Present)
+	assert.False(t, byName["CSIDriver/"+templates.CephFsDriverName].Present)
+	assert.False(t, byName["Deployment/"+templates.CephFsDriverName+csiCtrlPluginSuffix].Present)
+	assert.Equal(t, ptr.To(false), byName["Deployment/"+console.DeploymentName].Ready)
+	assert.True(t, byName["SecurityContextConstraints/"+templates.SCCName].Present)
+	assert.False(t, byName["ValidatingWebhookConfiguration/"+templates.SubscriptionWebhookName].Present)
+	assert.True(t, byName["ConsolePlugin/"+console.PluginName].Present)
+
+	recorder := httptest.NewRecorder()
+	NewHealthSummaryHandler(kubeClient, testNamespace).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/health", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	var served []ResourceHealth
+	assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &served))
+	assert.Equal(t, summary, served)
+}
+
+func TestReconcileCSINamespaceLabels(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: testNamespace, Labels: map[string]string{"team": "storage"}},
+	}
+	r := newSMSReconciler(t, namespace)
+	r.operatorConfigMap.Data = map[string]string{
+		csiNamespaceLabelsKey: "pod-security.kubernetes.io/enforce: privileged\npod-security.kubernetes.io/audit: \"privileged\"",
+	}
+
+	assert.NoError(t, r.reconcileCSINamespaceLabels())
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(namespace), namespace))
+	assert.Equal(t, map[string]string{
+		"team":                               "storage",
+		"pod-security.kubernetes.io/enforce": "privileged",
+		"pod-security.kubernetes.io/audit":   "privileged",
+	}, namespace.Labels)
+
+	r.operatorConfigMap.Data[csiNamespaceLabelsKey] = "invalid key: value"
+	assert.Error(t, r.reconcileCSINamespaceLabels())
+}
+
+func TestDisableMeshInjection(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testNamespace,
+			Labels:      map[string]string{"team": "storage"},
+			Annotations: map[string]string{"openshift.io/sa.scc.uid-range": "1000/10000"},
+		},
+	}
+	r := newSMSReconciler(t, namespace)
+	r.operatorConfigMap.Data = map[string]string{
+		disableMeshInjectionKey: "true",
+		csiNamespaceLabelsKey:   "pod-security.kubernetes.io/enforce: privileged",
+	}
+
+	assert.NoError(t, r.reconcileCSINamespaceLabels())
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(namespace), namespace))
+	assert.Equal(t, map[string]string{
+		"team":                               "storage",
+		"pod-security.kubernetes.io/enforce": "privileged",
+		istioInjectionLabelKey:               meshInjectionDisabledValue,
+	}, namespace.Labels)
+	assert.Equal(t, map[string]string{
+		"openshift.io/sa.scc.uid-range": "1000/10000",
+		linkerdInjectAnnotationKey:      meshInjectionDisabledValue,
+	}, namespace.Annotations)
+
+	// the injection label configured by the user takes precedence
+	r.operatorConfigMap.Data[csiNamespaceLabelsKey] = istioInjectionLabelKey + ": enabled"
+	assert.NoError(t, r.reconcileCSINamespaceLabels())
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(namespace), namespace))
+	assert.Equal(t, "enabled", namespace.Labels[istioInjectionLabelKey])
+}
+
+func TestCSINamespacePodSecurity(t *testing.T) {
+	newNamespace := func(level string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   testNamespace,
+				Labels: map[string]string{podSecurityEnforceLabelKey: level, "team": "storage"},
+			},
+		}
+	}
+
+	t.Run("report only", func(t *testing.T) {
+		r := newSMSReconciler(t, newNamespace("restricted"))
+		recorder := events.NewFakeRecorder(10)
+		r.Recorder = recorder
+
+		assert.NoError(t, r.reconcileCSINamespaceLabels())
+		assert.NoError(t, r.reportCSINamespacePodSecurity())
+		condition := r.getCondition(conditionTypeCSIPodSecurityAdmitted)
+		assert.NotNil(t, condition)
+		assert.Equal(t, metav1.ConditionFalse, condition.Status)
+		assert.Equal(t, "PrivilegedPodsBlocked", condition.Reason)
+		assert.Contains(t, condition.Message, `enforces the "restricted" pod security level`)
+		assert.Len(t, recorder.Events, 1)
+		assert.Contains(t, <-recorder.Events, "Warning CSIPodSecurityBlocked")
+
+		// the unchanged condition isn't reported again
+		assert.NoError(t, r.reportCSINamespacePodSecurity())
+		assert.Empty(t, recorder.Events)
+
+		// the namespace is left as is
+		namespace := &corev1.Namespace{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: testNamespace}, namespace))
+		assert.Equal(t, "restricted", namespace.Labels[podSecurityEnforceLabelKey])
+	})
+
+	t.Run("auto apply", func(t *testing.T) {
+		r := newSMSReconciler(t, newNamespace("baseline"))
+		recorder := events.NewFakeRecorder(10)
+		r.Recorder = recorder
+		r.operatorConfigMap.Data = map[string]string{manageNamespacePodSecurityKey: "true"}
+
+		assert.NoError(t, r.reconcileCSINamespaceLabels())
+		assert.NoError(t, r.reportCSINamespacePodSecurity())
+		namespace := &corev1.Namespace{}
+		assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: testNamespace}, namespace))
+		assert.Equal(t, map[string]string{
+			podSecurityEnforceLabelKey: podSecurityPrivilegedLevel,
+			"team":                     "storage",
+		}, namespace.Labels)
+		assert.Equal(t, metav1.ConditionTrue, r.getCondition(conditionTypeCSIPodSecurityAdmitted).Status)
+		assert.Empty(t, recorder.Events)
+
+		// an enforce level set by the user takes precedence and is reported
+		r.operatorConfigMap.Data[csiNamespaceLabelsKey] = podSecurityEnforceLabelKey + ": restricted"
+		assert.NoError(t, r.reconcileCSINamespaceLabels())
+		assert.NoError(t, r.reportCSINamespacePodSecurity())
+		assert.Equal(t, metav1.ConditionFalse, r.getCondition(conditionTypeCSIPodSecurityAdmitted).Status)
+	})
+
+	t.Run("cluster default", func(t *testing.T) {
+		r := newSMSReconciler(t, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}})
+		assert.NoError(t, r.reportCSINamespacePodSecurity())
+		assert.Equal(t, metav1.ConditionTrue, r.getCondition(conditionTypeCSIPodSecurityAdmitted).Status)
+	})
+}
+
+func TestOperatorConfigMapConditions(t *testing.T) {
+	r := newSMSReconciler(t)
+
+	r.setCondition(conditionTypeDegraded, metav1.ConditionFalse, "AsExpected", "")
+	degraded := r.getCondition(conditionTypeDegraded)
+	assert.NotNil(t, degraded)
+	assert.Equal(t, metav1.ConditionFalse, degraded.Status)
+	assert.Equal(t, "AsExpected", degraded.Reason)
+	assert.False(t, degraded.LastTransitionTime.IsZero())
+	assert.Nil(t, r.getCondition("Unknown"))
+
+	// the transition time only moves when the status changes
+	past := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+	degraded.LastTransitionTime = past
+	r.setCondition(conditionTypeDegraded, metav1.ConditionFalse, "StillAsExpected", "")
+	degraded = r.getCondition(conditionTypeDegraded)
+	assert.True(t, past.Equal(&degraded.LastTransitionTime))
+	assert.Equal(t, "StillAsExpected", degraded.Reason)
+
+	r.setCondition(conditionTypeDegraded, metav1.ConditionTrue, "CircuitBreakerOpen", "failing")
+	degraded = r.getCondition(conditionTypeDegraded)
+	assert.True(t, degraded.LastTransitionTime.After(past.Time))
+
+	// reporting writes the conditions into the status configmap and leaves the operator configmap untouched
+	r.setCondition("Other", metav1.ConditionTrue, "Test", "")
+	r.operatorConfigMap.Data = map[string]string{retainOnUninstallKey: "true"}
+	assert.NoError(t, r.Update(r.ctx, r.operatorConfigMap))
+	assert.NoError(t, r.reportConditions())
+
+	cm := &corev1.ConfigMap{}
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(r.operatorConfigMap), cm))
+	assert.Equal(t, map[string]string{retainOnUninstallKey: "true"}, cm.Data)
+	status := &corev1.ConfigMap{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: operatorStatusConfigMapName, Namespace: testNamespace}, status))
+	assert.True(t, isOwnedByOperatorConfigMap(status))
+	assert.Contains(t, status.Data[statusConditionsKey], "CircuitBreakerOpen")
+
+	// the reported conditions survive the next reconcile and are merged with newly set ones
+	r.loadConditions()
+	assert.Len(t, r.conditions, 2)
+	assert.Equal(t, metav1.ConditionTrue, r.getCondition(conditionTypeDegraded).Status)
+	r.setCondition("Another", metav1.ConditionFalse, "Test", "")
+	assert.Len(t, r.conditions, 3)
+	assert.Equal(t, metav1.ConditionTrue, r.getCondition("Other").Status)
+
+	// unchanged conditions aren't written again
+	r.conditions = r.conditions[:2]
+	resourceVersion := status.ResourceVersion
+	assert.NoError(t, r.reportConditions())
+	assert.NoError(t, r.Get(r.ctx, client.ObjectKeyFromObject(status), status))
+	assert.Equal(t, resourceVersion, status.ResourceVersion)
+}
+
+func TestReportRequiredPermissions(t *testing.T) {
+	r := newSMSReconciler(t)
+	reviews := 0
+	r.Client = newFakeClientBuilder(r.Scheme).
+		WithObjects(r.operatorConfigMap).
+		WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review, ok := obj.(*authorizationv1.SelfSubjectAccessReview)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				reviews++
+				attributes := review.Spec.ResourceAttributes
+				review.Status.Allowed = attributes.Resource != "prometheusrules"
+				if attributes.Resource == "securitycontextconstraints" || attributes.Resource == "consoleplugins" {
+					assert.Empty(t, attributes.Namespace)
+				} else {
+					assert.Equal(t, testNamespace, attributes.Namespace)
+				}
+				return nil
+			},
+		}).
+		Build()
+
+	assert.NoError(t, r.reportRequiredPermissions())
+	assert.Equal(t, len(requiredPermissions), reviews)
+	assert.True(t, r.permissionsChecked)
+	condition := r.getCondition(conditionTypePermissionsAvailable)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "AccessDenied", condition.Reason)
+	assert.Equal(t, "missing permissions: create prometheusrules.monitoring.coreos.com", condition.Message)
+
+	// the condition is persisted right away
+	status := &corev1.ConfigMap{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: operatorStatusConfigMapName, Namespace: testNamespace}, status))
+	assert.Contains(t, status.Data[statusConditionsKey], "create prometheusrules.monitoring.coreos.com")
+}
@@ -819,7 +819,7 @@ func (r *storageClientReconcile) offboardConsumer(externalClusterClient *provide
 }
 
 func (r *storageClientReconcile) reconcileClientStatusReporterJob(operatorVersion string) (reconcile.Result, error) {
-	clientSubscription, err := getSubscriptionByPackageName(r.ctx, r.Client, r.OperatorNamespace, "ocs-client-operator")
+	clientSubscription, err := getClientOperatorSubscription(r.ctx, r.Client, r.OperatorNamespace)
 	if err != nil {
 		return reconcile.Result{}, err
 	}
@@ -1012,7 +1012,10 @@ func (r *storageClientReconcile) update(obj client.Object, opts ...client.Update
 	return r.Update(r.ctx, obj, opts...)
 }
 
+// own makes dependent controlled by the storage client. The owned-by label marks it as created by this operator like
+// the resources of the operator configmap, the controller reference keeps isOwnedByOperatorConfigMap from claiming it.
 func (r *storageClientReconcile) own(dependent metav1.Object) error {
+	utils.AddLabel(dependent, OwnedByLabelKey, ManagedByLabelValue)
 	return controllerutil.SetControllerReference(&r.storageClient, dependent, r.Scheme)
 }
 
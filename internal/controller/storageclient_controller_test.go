@@ -103,6 +103,30 @@ func TestReconcileResourcesByGK_VolumeAttributesClass_Create(t *testing.T) {
 	assert.True(t, metav1.IsControlledBy(created, &r.storageClient))
 }
 
+func TestReconcileResourcesByGK_OwnedByLabel(t *testing.T) {
+	r := newFakeStorageClientReconcile(t)
+
+	desiredObjects := map[string]kubeObjectWithOpRecords{
+		"VolumeAttributesClass.storage.k8s.io": {
+			{
+				NamespacedName: types.NamespacedName{Name: "test-vac"},
+				bytes:          newVolumeAttributesClassBytes(t, "test-vac", templates.RBDDriverName, nil),
+				operation:      provider.KubeClientOp_CREATE_OR_UPDATE,
+			},
+		},
+	}
+
+	var combinedErr error
+	r.reconcileResourcesByGK(&storagev1.VolumeAttributesClass{}, desiredObjects, &combinedErr)
+	assert.NoError(t, combinedErr)
+
+	created := &storagev1.VolumeAttributesClass{}
+	assert.NoError(t, r.Get(r.ctx, types.NamespacedName{Name: "test-vac"}, created))
+	assert.Equal(t, ManagedByLabelValue, created.Labels[OwnedByLabelKey])
+	// the storage client stays the owner of the labeled dependents
+	assert.False(t, isOwnedByOperatorConfigMap(created))
+}
+
 func TestReconcileResourcesByGK_VolumeAttributesClass_Update(t *testing.T) {
 	r := newFakeStorageClientReconcile(t)
 
@@ -17,7 +17,7 @@ var (
 	pluginBasePath = "/"
 
 	NginxConfigMapName = fmt.Sprintf("%s-nginx-conf", DeploymentName)
-	pluginName         = "odf-client-console"
+	PluginName         = "odf-client-console"
 
 	pluginDisplayName = "ODF Client Console"
 
@@ -25,6 +25,9 @@ var (
 	serviceSecretAnnotation = "service.alpha.openshift.io/serving-cert-secret-name"
 
 	AppNameLabelKey = "app.kubernetes.io/name"
+
+	// ListenPortName names the container port nginx listens on in the console deployment
+	ListenPortName = "https"
 )
 
 //go:embed nginx_proxy.tmpl
@@ -33,7 +36,8 @@ var nginxProxyConf string
 //go:embed nginx_root.conf
 var nginxRootConf string
 
-func GetService(port int32, namespace string) *apiv1.Service {
+// GetService returns the console service exposing nginx, listening on targetPort, on servicePort.
+func GetService(targetPort, servicePort int32, namespace string) *apiv1.Service {
 	return &apiv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      DeploymentName,
@@ -49,8 +53,8 @@ func GetService(port int32, namespace string) *apiv1.Service {
 			Ports: []apiv1.ServicePort{
 				{
 					Protocol:   apiv1.ProtocolTCP,
-					TargetPort: intstr.IntOrString{IntVal: port},
-					Port:       port,
+					TargetPort: intstr.IntOrString{IntVal: targetPort},
+					Port:       servicePort,
 					Name:       servicePortName,
 				},
 			},
@@ -61,10 +65,10 @@ func GetService(port int32, namespace string) *apiv1.Service {
 	}
 }
 
-func GetConsolePlugin(consolePort int32, serviceNamespace string) *consolev1.ConsolePlugin {
+func GetConsolePlugin(consolePort int32, serviceNamespace string, forwardAuth bool) *consolev1.ConsolePlugin {
 	return &consolev1.ConsolePlugin{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: pluginName,
+			Name: PluginName,
 		},
 		Spec: consolev1.ConsolePluginSpec{
 			DisplayName: pluginDisplayName,
@@ -80,30 +84,46 @@ func GetConsolePlugin(consolePort int32, serviceNamespace string) *consolev1.Con
 					BasePath:  pluginBasePath,
 				},
 			},
-			Proxy: getConsolePluginProxy(consolePort, serviceNamespace),
+			Proxy: getConsolePluginProxy(consolePort, serviceNamespace, forwardAuth),
 		},
 	}
 }
 
-func GetNginxRootConf() string {
-	return nginxRootConf
+var nginxRootConfTemplate = template.Must(template.New("nginxRootConf").Parse(nginxRootConf))
+
+// GetNginxRootConf renders the root nginx config serving the console plugin on listenPort.
+func GetNginxRootConf(listenPort int32) (string, error) {
+	var sb strings.Builder
+	if err := nginxRootConfTemplate.Execute(&sb, struct{ ListenPort int32 }{listenPort}); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
 }
 
-func GetNginxProxyConf(uniqueIdentifier, exposeAs, endpointURL, endpointHost, certsPath string) (string, error) {
+// GetNginxProxyConf renders the nginx location proxying to the endpoint. forwardAuth passes the user's
+// Authorization header through to the endpoint, insecureSkipVerify disables the verification of its certificate
+// and disableCache replaces the caching headers of its responses with ones forbidding caching.
+func GetNginxProxyConf(uniqueIdentifier, exposeAs, endpointURL, endpointHost, certsPath string, forwardAuth, insecureSkipVerify, disableCache bool) (string, error) {
 	type nginxProxyConfData struct {
-		UniqueIdentifier string
-		ExposeAs         string
-		EndpointURL      string
-		EndpointHost     string
-		CertsPath        string
+		UniqueIdentifier   string
+		ExposeAs           string
+		EndpointURL        string
+		EndpointHost       string
+		CertsPath          string
+		ForwardAuth        bool
+		InsecureSkipVerify bool
+		DisableCache       bool
 	}
 
 	data := nginxProxyConfData{
-		UniqueIdentifier: uniqueIdentifier,
-		ExposeAs:         exposeAs,
-		EndpointURL:      endpointURL,
-		EndpointHost:     endpointHost,
-		CertsPath:        certsPath,
+		UniqueIdentifier:   uniqueIdentifier,
+		ExposeAs:           exposeAs,
+		EndpointURL:        endpointURL,
+		EndpointHost:       endpointHost,
+		CertsPath:          certsPath,
+		ForwardAuth:        forwardAuth,
+		InsecureSkipVerify: insecureSkipVerify,
+		DisableCache:       disableCache,
 	}
 
 	t, err := template.New("nginxProxyConf").Parse(nginxProxyConf)
@@ -117,7 +137,12 @@ func GetNginxProxyConf(uniqueIdentifier, exposeAs, endpointURL, endpointHost, ce
 	return sb.String(), nil
 }
 
-func getConsolePluginProxy(port int32, serviceNamespace string) []consolev1.ConsolePluginProxy {
+func getConsolePluginProxy(port int32, serviceNamespace string, forwardAuth bool) []consolev1.ConsolePluginProxy {
+	authorization := consolev1.None
+	if forwardAuth {
+		// console sets the logged in user's bearer token on the proxied requests
+		authorization = consolev1.UserToken
+	}
 	return []consolev1.ConsolePluginProxy{
 		{
 			Alias: "s3EndpointProxy",
@@ -129,7 +154,7 @@ func getConsolePluginProxy(port int32, serviceNamespace string) []consolev1.Cons
 					Port:      port,
 				},
 			},
-			Authorization: consolev1.None,
+			Authorization: authorization,
 		},
 	}
 }
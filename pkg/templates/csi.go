@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"slices"
 
+	"github.com/red-hat-storage/ocs-client-operator/pkg/utils"
+
 	csiopv1 "github.com/ceph/ceph-csi-operator/api/v1"
 	secv1 "github.com/openshift/api/security/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -16,6 +18,19 @@ const RBDDriverName = "openshift-storage.rbd.csi.ceph.com"
 const CephFsDriverName = "openshift-storage.cephfs.csi.ceph.com"
 const NfsDriverName = "openshift-storage.nfs.csi.ceph.com"
 
+// Names of the containers in the CSI pods deployed by ceph-csi-operator, used as keys for container extra args
+const RBDPluginContainerName = "csi-rbdplugin"
+const CephFsPluginContainerName = "csi-cephfsplugin"
+const NfsPluginContainerName = "csi-nfsplugin"
+const ProvisionerContainerName = "csi-provisioner"
+const AttacherContainerName = "csi-attacher"
+const ResizerContainerName = "csi-resizer"
+const SnapshotterContainerName = "csi-snapshotter"
+const SnapshotMetadataContainerName = "csi-snapshot-metadata"
+
+// CSISocketDir is the directory shared by the plugin and sidecar containers for the CSI unix socket
+const CSISocketDir = "/csi"
+
 // Snapshot metadata sidecar automation (KEP-3314 / CBT)
 const SnapshotMetadataServiceName = "openshift-storage-rbd-snapshot-metadata"
 const SnapshotMetadataTLSSecretName = "openshift-storage-rbd-snapshot-metadata-tls"
@@ -48,6 +63,38 @@ func InjectSnapshotMetadataTLSVolume(cp *csiopv1.ControllerPluginSpec) {
 	}
 }
 
+// Cluster wide trust bundle, injected by OpenShift into the labeled configmap in the operator namespace
+const TrustedCABundleConfigMapName = "ocs-client-operator-trusted-ca-bundle"
+const TrustedCABundleInjectLabelKey = "config.openshift.io/inject-trusted-cabundle"
+const trustedCABundleVolumeName = "trusted-ca-bundle"
+
+// SetTrustedCABundleVolume mounts the trusted ca bundle configmap at the system ca path of the CSI containers
+// if enabled, and removes the mount otherwise.
+func SetTrustedCABundleVolume(pc *csiopv1.PodCommonSpec, enabled bool) {
+	pc.Volumes = slices.DeleteFunc(pc.Volumes, func(v csiopv1.VolumeSpec) bool {
+		return v.Volume.Name == trustedCABundleVolumeName
+	})
+	if !enabled {
+		return
+	}
+	pc.Volumes = append(pc.Volumes, csiopv1.VolumeSpec{
+		Volume: corev1.Volume{
+			Name: trustedCABundleVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				ConfigMap: &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: TrustedCABundleConfigMapName},
+					Items:                []corev1.KeyToPath{{Key: "ca-bundle.crt", Path: "tls-ca-bundle.pem"}},
+				},
+			},
+		},
+		Mount: corev1.VolumeMount{
+			Name:      trustedCABundleVolumeName,
+			MountPath: "/etc/pki/ca-trust/extracted/pem",
+			ReadOnly:  true,
+		},
+	})
+}
+
 // security context constraints
 const SCCName = "ceph-csi-op-scc"
 
@@ -90,21 +137,55 @@ var securityContextConstraints = secv1.SecurityContextConstraints{
 	},
 }
 
-func SetSecurityContextConstraintsDesiredState(scc *secv1.SecurityContextConstraints, ns string) {
-	// Make sure metadata is preserved
+// csiServiceAccountPrefixes maps the CSI driver names to the prefix of their plugin service accounts
+var csiServiceAccountPrefixes = map[string]string{
+	CephFsDriverName: "ceph-csi-cephfs",
+	NfsDriverName:    "ceph-csi-nfs",
+	RBDDriverName:    "ceph-csi-rbd",
+}
+
+// SetSecurityContextConstraintsDesiredState grants the SCC to the plugin service accounts of driverNames and revokes
+// it from those of the other drivers, keeping the users granted outside of the operator. The nvmeof service accounts
+// are always included as that driver isn't deployed by this operator.
+func SetSecurityContextConstraintsDesiredState(scc *secv1.SecurityContextConstraints, ns string, driverNames []string) {
+	// Make sure metadata and users are preserved
 	metadata := scc.ObjectMeta
+	users := scc.Users
 	securityContextConstraints.DeepCopyInto(scc)
 	scc.ObjectMeta = metadata
 
-	scc.Users = []string{
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-cephfs-ctrlplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-cephfs-nodeplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-nfs-ctrlplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-nfs-nodeplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-nvmeof-ctrlplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-nvmeof-nodeplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-rbd-ctrlplugin-sa", ns),
-		fmt.Sprintf("system:serviceaccount:%s:ceph-csi-rbd-nodeplugin-sa", ns),
+	prefixes := []string{"ceph-csi-nvmeof"}
+	for _, driverName := range driverNames {
+		if prefix, ok := csiServiceAccountPrefixes[driverName]; ok {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	slices.Sort(prefixes)
+	prefixes = slices.Compact(prefixes)
+
+	for _, prefix := range csiServiceAccountPrefixes {
+		if utils.Contains(prefixes, prefix) {
+			continue
+		}
+		for _, user := range getPluginServiceAccountUsers(ns, prefix) {
+			users = utils.Remove(users, user)
+		}
+	}
+	for _, prefix := range prefixes {
+		for _, user := range getPluginServiceAccountUsers(ns, prefix) {
+			if !utils.Contains(users, user) {
+				users = append(users, user)
+			}
+		}
+	}
+	scc.Users = users
+}
+
+// getPluginServiceAccountUsers returns the SCC users of the controller and node plugin service accounts with prefix
+func getPluginServiceAccountUsers(ns, prefix string) []string {
+	return []string{
+		fmt.Sprintf("system:serviceaccount:%s:%s-ctrlplugin-sa", ns, prefix),
+		fmt.Sprintf("system:serviceaccount:%s:%s-nodeplugin-sa", ns, prefix),
 	}
 }
 
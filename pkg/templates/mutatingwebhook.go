@@ -0,0 +1,38 @@
+package templates
+
+import (
+	admrv1 "k8s.io/api/admissionregistration/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	ConfigMapDefaultingWebhookName = "configmap.ocs.openshift.io"
+	ConfigMapDefaultingWebhookPath = "/mutate-configmap"
+)
+
+var ConfigMapDefaultingMutatingWebhook = admrv1.MutatingWebhook{
+	ClientConfig: admrv1.WebhookClientConfig{
+		Service: &admrv1.ServiceReference{
+			Name: WebhookServiceName,
+			Path: ptr.To(ConfigMapDefaultingWebhookPath),
+			Port: ptr.To(int32(443)),
+		},
+	},
+	Rules: []admrv1.RuleWithOperations{
+		{
+			Rule: admrv1.Rule{
+				APIGroups:   []string{""},
+				APIVersions: []string{"v1"},
+				Resources:   []string{"configmaps"},
+				Scope:       ptr.To(admrv1.NamespacedScope),
+			},
+			Operations: []admrv1.OperationType{admrv1.Create, admrv1.Update},
+		},
+	},
+	SideEffects:             ptr.To(admrv1.SideEffectClassNone),
+	TimeoutSeconds:          ptr.To(int32(30)),
+	AdmissionReviewVersions: []string{"v1"},
+	ReinvocationPolicy:      ptr.To(admrv1.NeverReinvocationPolicy),
+	// defaults are also applied by the reconciler, don't block configmap writes if webhook can't be reached
+	FailurePolicy: ptr.To(admrv1.Ignore),
+}
@@ -60,6 +60,8 @@ const (
 
 	// ConfigMap key for topology configuration
 	TopologyFailureDomainLabelsKey = "topologyFailureDomainLabels"
+	// ConfigMap key for topology configuration, taking precedence over TopologyFailureDomainLabelsKey
+	CSITopologyDomainLabelsKey = "CSI_TOPOLOGY_DOMAIN_LABELS"
 
 	CronScheduleWeekly = "@weekly"
 
@@ -119,23 +121,37 @@ func AddLabels(obj metav1.Object, newLabels map[string]string) {
 	labels := obj.GetLabels()
 	if labels == nil {
 		labels = map[string]string{}
-		obj.SetLabels(labels)
 	}
 	maps.Copy(labels, newLabels)
+	// set the labels back as unstructured objects return a copy of them
+	obj.SetLabels(labels)
 }
 
 // AddAnnotation adds label to a resource metadata, returns true if added else false
 func AddLabel(obj metav1.Object, key string, value string) bool {
 	labels := obj.GetLabels()
+	if oldValue, exist := labels[key]; exist && oldValue == value {
+		return false
+	}
 	if labels == nil {
 		labels = map[string]string{}
-		obj.SetLabels(labels)
 	}
-	if oldValue, exist := labels[key]; !exist || oldValue != value {
-		labels[key] = value
-		return true
+	labels[key] = value
+	// set the labels back as unstructured objects return a copy of them
+	obj.SetLabels(labels)
+	return true
+}
+
+// RemoveLabel removes a label from a resource metadata, returns true if removed else false
+func RemoveLabel(obj metav1.Object, key string) bool {
+	labels := obj.GetLabels()
+	if _, exist := labels[key]; !exist {
+		return false
 	}
-	return false
+	delete(labels, key)
+	// set the labels back as unstructured objects return a copy of them
+	obj.SetLabels(labels)
+	return true
 }
 
 func AddAnnotations(obj metav1.Object, newAnnotations map[string]string) {
@@ -3,7 +3,10 @@ package utils
 import (
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 func TestAdjustCPU(t *testing.T) {
@@ -49,3 +52,67 @@ func TestAdjustCPU(t *testing.T) {
 		})
 	}
 }
+
+func TestAddLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  metav1.Object
+	}{
+		{
+			name: "typed object",
+			obj:  &corev1.ConfigMap{},
+		},
+		{
+			name: "unstructured object",
+			obj:  &unstructured.Unstructured{Object: map[string]any{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !AddLabel(tt.obj, "key", "value") {
+				t.Errorf("expected the label to be reported as added")
+			}
+			if got := tt.obj.GetLabels()["key"]; got != "value" {
+				t.Errorf("expected the label to be set, got %q", got)
+			}
+			if AddLabel(tt.obj, "key", "value") {
+				t.Errorf("expected an unchanged label not to be reported as added")
+			}
+		})
+	}
+}
+
+func TestRemoveLabel(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  metav1.Object
+	}{
+		{
+			name: "typed object",
+			obj:  &corev1.ConfigMap{},
+		},
+		{
+			name: "unstructured object",
+			obj:  &unstructured.Unstructured{Object: map[string]any{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if RemoveLabel(tt.obj, "key") {
+				t.Errorf("expected a missing label not to be reported as removed")
+			}
+			tt.obj.SetLabels(map[string]string{"key": "value", "other": "value"})
+			if !RemoveLabel(tt.obj, "key") {
+				t.Errorf("expected the label to be reported as removed")
+			}
+			if _, exist := tt.obj.GetLabels()["key"]; exist {
+				t.Errorf("expected the label to be removed")
+			}
+			if got := tt.obj.GetLabels()["other"]; got != "value" {
+				t.Errorf("expected the other labels to be kept, got %q", got)
+			}
+		})
+	}
+}
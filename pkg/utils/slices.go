@@ -16,7 +16,10 @@ limitations under the License.
 
 package utils
 
-import "os"
+import (
+	"os"
+	"slices"
+)
 
 // Find returns the first entry matching the function "f" or else return nil
 func Find[T any](list []T, f func(item *T) bool) *T {
@@ -44,3 +47,15 @@ func Filter[T any](s []T, predicate func(*T) bool) []T {
 	}
 	return result
 }
+
+// Contains reports whether v is present in s
+func Contains[T comparable](s []T, v T) bool {
+	return slices.Contains(s, v)
+}
+
+// Remove returns a copy of s with every occurrence of v removed, s itself is left untouched
+func Remove[T comparable](s []T, v T) []T {
+	return Filter(s, func(item *T) bool {
+		return *item != v
+	})
+}
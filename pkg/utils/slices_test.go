@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     []string
+		value    string
+		expected bool
+	}{
+		{
+			name:     "nil slice",
+			list:     nil,
+			value:    "a",
+			expected: false,
+		},
+		{
+			name:     "empty slice",
+			list:     []string{},
+			value:    "a",
+			expected: false,
+		},
+		{
+			name:     "value present",
+			list:     []string{"a", "b"},
+			value:    "b",
+			expected: true,
+		},
+		{
+			name:     "value absent",
+			list:     []string{"a", "b"},
+			value:    "c",
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Contains(tt.list, tt.value); got != tt.expected {
+				t.Fatalf("expected %t, got %t", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	tests := []struct {
+		name     string
+		list     []string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "nil slice",
+			list:     nil,
+			value:    "a",
+			expected: []string{},
+		},
+		{
+			name:     "empty slice",
+			list:     []string{},
+			value:    "a",
+			expected: []string{},
+		},
+		{
+			name:     "value absent",
+			list:     []string{"a", "b"},
+			value:    "c",
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "duplicates are removed",
+			list:     []string{"a", "b", "a", "c", "a"},
+			value:    "a",
+			expected: []string{"b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := slices.Clone(tt.list)
+			got := Remove(tt.list, tt.value)
+			if !slices.Equal(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+			if !slices.Equal(tt.list, original) {
+				t.Fatalf("input slice was modified: %v", tt.list)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+/*
+Copyright 2022 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseBool parses boolean values written by hand, e.g. in the operator configmap. Surrounding whitespace is
+// ignored and, regardless of case, "true", "t", "yes", "y", "on" and "1" are true while "false", "f", "no", "n",
+// "off" and "0" are false.
+func ParseBool(str string) (bool, error) {
+	value := strings.ToLower(strings.TrimSpace(str))
+	switch value {
+	case "yes", "y", "on":
+		return true, nil
+	case "no", "n", "off":
+		return false, nil
+	}
+	if b, err := strconv.ParseBool(value); err == nil {
+		return b, nil
+	}
+	return false, fmt.Errorf("invalid boolean %q: expected one of true/false, yes/no, on/off, t/f, y/n or 1/0", str)
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestParseBool(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		expected  bool
+		expectErr bool
+	}{
+		{name: "true", value: "true", expected: true},
+		{name: "mixed case with trailing space", value: "True ", expected: true},
+		{name: "yes", value: "yes", expected: true},
+		{name: "upper case yes", value: "YES", expected: true},
+		{name: "y", value: "y", expected: true},
+		{name: "on", value: " on", expected: true},
+		{name: "one", value: "1", expected: true},
+		{name: "false", value: "false"},
+		{name: "no", value: "No"},
+		{name: "n", value: "n"},
+		{name: "off", value: "OFF"},
+		{name: "zero", value: "0"},
+		{name: "empty", value: "", expectErr: true},
+		{name: "whitespace only", value: "  ", expectErr: true},
+		{name: "unknown word", value: "sometimes", expectErr: true},
+		{name: "quoted", value: `"true"`, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBool(tt.value)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("ParseBool(%q) = %t, expected an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseBool(%q) returned unexpected error: %v", tt.value, err)
+			}
+			if got != tt.expected {
+				t.Errorf("ParseBool(%q) = %t, expected %t", tt.value, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type ConfigMapDefaulter struct {
+	Decoder admission.Decoder
+	Log     logr.Logger
+	// Name of the configmap that should be defaulted, reviews for other configmaps are allowed as is
+	Name string
+	// Defaults are set on the keys which are missing from the configmap
+	Defaults map[string]string
+}
+
+func (d *ConfigMapDefaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	configMap := &corev1.ConfigMap{}
+	if err := d.Decoder.Decode(req, configMap); err != nil {
+		d.Log.Error(err, "failed to decode admission review as configmap")
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("only configmaps admission reviews are supported: %v", err))
+	}
+
+	if configMap.Name != d.Name {
+		return admission.Allowed(fmt.Sprintf("configmap %q is not defaulted", configMap.Name))
+	}
+
+	var defaultedKeys []string
+	for key, value := range d.Defaults {
+		if _, exist := configMap.Data[key]; exist {
+			continue
+		}
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[key] = value
+		defaultedKeys = append(defaultedKeys, key)
+	}
+	if len(defaultedKeys) == 0 {
+		return admission.Allowed("all configmap keys are already set")
+	}
+
+	marshaledConfigMap, err := json.Marshal(configMap)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to marshal defaulted configmap: %v", err))
+	}
+
+	d.Log.Info("Defaulting configmap keys", "keys", defaultedKeys)
+	return admission.PatchResponseFromRaw(req.Object.Raw, marshaledConfigMap)
+}
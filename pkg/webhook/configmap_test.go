@@ -0,0 +1,98 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+func newConfigMapAdmissionRequest(t *testing.T, configMap *corev1.ConfigMap) admission.Request {
+	raw, err := json.Marshal(configMap)
+	assert.NoError(t, err)
+	return admission.Request{
+		AdmissionRequest: admissionv1.AdmissionRequest{
+			Operation: admissionv1.Create,
+			Object:    runtime.RawExtension{Raw: raw},
+		},
+	}
+}
+
+func TestConfigMapDefaulter(t *testing.T) {
+	const configMapName = "ocs-client-operator-config"
+	defaults := map[string]string{
+		"disableVersionChecks": "false",
+		"alertPollInterval":    "1m0s",
+	}
+
+	tests := []struct {
+		name            string
+		configMap       *corev1.ConfigMap
+		expectedPatches map[string]any
+	}{
+		{
+			name: "missing keys are defaulted",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapName},
+			},
+			expectedPatches: map[string]any{
+				"/data": map[string]any{"disableVersionChecks": "false", "alertPollInterval": "1m0s"},
+			},
+		},
+		{
+			name: "only missing keys are defaulted",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapName},
+				Data:       map[string]string{"disableVersionChecks": "true"},
+			},
+			expectedPatches: map[string]any{
+				"/data/alertPollInterval": "1m0s",
+			},
+		},
+		{
+			name: "no-op when all keys are present",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapName},
+				Data: map[string]string{
+					"disableVersionChecks": "true",
+					"alertPollInterval":    "5m",
+				},
+			},
+		},
+		{
+			name: "other configmaps are not defaulted",
+			configMap: &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defaulter := &ConfigMapDefaulter{
+				Decoder:  admission.NewDecoder(kubescheme.Scheme),
+				Log:      logr.Discard(),
+				Name:     configMapName,
+				Defaults: defaults,
+			}
+
+			resp := defaulter.Handle(context.Background(), newConfigMapAdmissionRequest(t, tt.configMap))
+			assert.True(t, resp.Allowed)
+			assert.Len(t, resp.Patches, len(tt.expectedPatches))
+			for _, patch := range resp.Patches {
+				expectedValue, ok := tt.expectedPatches[patch.Path]
+				assert.True(t, ok, "unexpected patch %v", patch)
+				assert.Equal(t, "add", patch.Operation)
+				assert.Equal(t, expectedValue, patch.Value)
+			}
+		})
+	}
+}
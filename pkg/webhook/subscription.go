@@ -9,6 +9,7 @@ import (
 	opv1a1 "github.com/operator-framework/api/pkg/operators/v1alpha1"
 	"github.com/red-hat-storage/ocs-client-operator/api/v1alpha1"
 	"github.com/red-hat-storage/ocs-client-operator/pkg/utils"
+	admissionv1 "k8s.io/api/admission/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -22,6 +23,11 @@ type SubscriptionAdmission struct {
 func (s *SubscriptionAdmission) Handle(ctx context.Context, req admission.Request) admission.Response {
 	s.Log.Info("Request received for admission review")
 
+	// deleting a subscription doesn't change the subscribed channel
+	if req.Operation == admissionv1.Delete {
+		return admission.Allowed("subscription deletion doesn't violate desired subscription channel")
+	}
+
 	// review should be for a subscription
 	subscription := &opv1a1.Subscription{}
 	if err := s.Decoder.Decode(req, subscription); err != nil {